@@ -0,0 +1,71 @@
+package goflags
+
+import (
+	"sort"
+	"strings"
+)
+
+// expandAbbreviations returns a copy of the arguments with the unambiguous
+// prefixes of long flag names replaced by the full names, walking them like
+// the flag package does until the first non-flag argument or "--".
+func (flagSet *FlagSet) expandAbbreviations(args []string) ([]string, error) {
+	expanded := append([]string(nil), args...)
+	for i := 0; i < len(expanded); i++ {
+		arg := expanded[i]
+		if len(arg) < 2 || arg[0] != '-' || arg == "--" {
+			break
+		}
+		dashes := "-"
+		if strings.HasPrefix(arg, "--") {
+			dashes = "--"
+		}
+		name, value, hasValue := strings.Cut(arg[len(dashes):], "=")
+		if name == "" || name[0] == '-' || name[0] == '=' {
+			// bad flag syntax is reported by the flag package
+			break
+		}
+		currentFlag := flagSet.CommandLine.Lookup(name)
+		if currentFlag == nil && !isHelpArg("-"+name) {
+			candidates := flagSet.abbreviationCandidates(name)
+			switch len(candidates) {
+			case 0:
+				// unknown flags are reported by the flag package
+			case 1:
+				currentFlag = flagSet.CommandLine.Lookup(candidates[0])
+				expanded[i] = dashes + candidates[0]
+				if hasValue {
+					expanded[i] += "=" + value
+				}
+			default:
+				return nil, newParseError(CategoryUnknown, name, "", "ambiguous flag -%v: could be -%v", name, strings.Join(candidates, ", -"))
+			}
+		}
+		if currentFlag == nil {
+			continue
+		}
+		if boolFlag, ok := currentFlag.Value.(interface{ IsBoolFlag() bool }); ok && boolFlag.IsBoolFlag() {
+			continue
+		}
+		if !hasValue {
+			// the next argument is the value of the flag
+			i++
+		}
+	}
+	return expanded, nil
+}
+
+// abbreviationCandidates returns the sorted long flag names starting with the prefix
+func (flagSet *FlagSet) abbreviationCandidates(prefix string) []string {
+	var candidates []string
+	flagSet.flagKeys.forEach(func(key string, data *FlagData) {
+		if key != data.long || !strings.HasPrefix(key, prefix) {
+			return
+		}
+		if flagSet.CommandLine.Lookup(key) == nil {
+			return
+		}
+		candidates = append(candidates, key)
+	})
+	sort.Strings(candidates)
+	return candidates
+}
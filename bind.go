@@ -0,0 +1,102 @@
+package goflags
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// BindStruct copies the current (post-parse) values of the flags into the
+// fields of the struct pointed to by v tagged with `flag:"name"` (short, long
+// or alias name). Values are converted to the field type when possible
+// (ex: StringSlice to []string, int to int64) and fields of string type
+// receive the string form of any flag.
+func (flagSet *FlagSet) BindStruct(v interface{}) error {
+	structValue := reflect.ValueOf(v)
+	if structValue.Kind() != reflect.Ptr || structValue.IsNil() || structValue.Elem().Kind() != reflect.Struct {
+		return errors.New("bind target must be a non-nil pointer to a struct")
+	}
+	structValue = structValue.Elem()
+	structType := structValue.Type()
+
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		name, ok := field.Tag.Lookup("flag")
+		if !ok || name == "" || name == "-" {
+			continue
+		}
+		if !field.IsExported() {
+			return fmt.Errorf("field %s bound to flag -%v is not exported", field.Name, name)
+		}
+		currentFlag := flagSet.Lookup(name)
+		if currentFlag == nil {
+			return fmt.Errorf("no such flag -%v for field %s", name, field.Name)
+		}
+		fieldValue := structValue.Field(i)
+
+		value := reflect.ValueOf(flagValue(currentFlag))
+		if !value.IsValid() {
+			// values without flag.Getter are bound from their underlying value
+			value = reflect.Indirect(reflect.ValueOf(currentFlag.Value))
+		}
+		switch {
+		case value.Type().AssignableTo(field.Type):
+			fieldValue.Set(value)
+		case value.Type().ConvertibleTo(field.Type) && (field.Type.Kind() != reflect.String || value.Kind() == reflect.String):
+			if !exactConversion(value, field.Type) {
+				return fmt.Errorf("cannot bind flag -%v value %v to field %s of type %s without loss", name, value, field.Name, field.Type)
+			}
+			fieldValue.Set(value.Convert(field.Type))
+		case field.Type.Kind() == reflect.String:
+			fieldValue.SetString(currentFlag.Value.String())
+		default:
+			return fmt.Errorf("cannot bind flag -%v of type %s to field %s of type %s", name, value.Type(), field.Name, field.Type)
+		}
+	}
+	return nil
+}
+
+// exactConversion returns whether a numeric value converts to the target
+// type without overflow or precision loss. Integers are compared as integers,
+// floats are only used for float types. Non numeric values always convert.
+func exactConversion(value reflect.Value, target reflect.Type) bool {
+	converted := value.Convert(target)
+	switch {
+	case value.CanInt():
+		switch {
+		case converted.CanInt():
+			return converted.Int() == value.Int()
+		case converted.CanUint():
+			return value.Int() >= 0 && converted.Uint() == uint64(value.Int())
+		case converted.CanFloat():
+			return exactIntFloat(value.Int(), converted.Float())
+		}
+	case value.CanUint():
+		switch {
+		case converted.CanInt():
+			return converted.Int() >= 0 && uint64(converted.Int()) == value.Uint()
+		case converted.CanUint():
+			return converted.Uint() == value.Uint()
+		case converted.CanFloat():
+			return value.Uint() <= math.MaxInt64 && exactIntFloat(int64(value.Uint()), converted.Float())
+		}
+	case value.CanFloat():
+		switch {
+		case converted.CanFloat():
+			return converted.Float() == value.Float()
+		case converted.CanInt(), converted.CanUint():
+			return false
+		}
+	}
+	return true
+}
+
+// exactIntFloat returns whether a float is the exact value of an integer
+func exactIntFloat(integer int64, float float64) bool {
+	// float64(math.MaxInt64) rounds up to 2^63, outside of the int64 range
+	if float < math.MinInt64 || float >= math.MaxInt64 {
+		return false
+	}
+	return int64(float) == integer
+}
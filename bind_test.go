@@ -0,0 +1,92 @@
+package goflags
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBindStruct(t *testing.T) {
+	flagSet := NewFlagSet()
+	flagSet.DisableConfigLoading(true)
+	var target, proxy string
+	var threads int
+	var silent bool
+	var timeout time.Duration
+	var headers StringSlice
+	flagSet.StringVarP(&target, "target", "t", "", "target")
+	flagSet.URLVar(&proxy, "proxy", "", "proxy url")
+	flagSet.IntVarP(&threads, "threads", "c", 10, "threads")
+	flagSet.BoolVar(&silent, "silent", false, "silent mode")
+	flagSet.DurationVar(&timeout, "timeout", 5*time.Second, "timeout")
+	flagSet.StringSliceVarP(&headers, "header", "H", nil, "headers", CommaSeparatedStringSliceOptions)
+
+	err := flagSet.ParseArgs([]string{"-t", "example.com", "-proxy", "http://127.0.0.1:8080", "-c", "25", "-silent", "-H", "a:b,c:d"})
+	require.Nil(t, err)
+
+	var options struct {
+		Target  string        `flag:"target"`
+		Proxy   string        `flag:"proxy"`
+		Threads int64         `flag:"c"`
+		Silent  bool          `flag:"silent"`
+		Timeout time.Duration `flag:"timeout"`
+		Headers []string      `flag:"header"`
+		Ignored string
+	}
+	err = flagSet.BindStruct(&options)
+	require.Nil(t, err, "could not bind struct")
+	require.Equal(t, "example.com", options.Target)
+	require.Equal(t, "http://127.0.0.1:8080", options.Proxy)
+	require.Equal(t, int64(25), options.Threads)
+	require.True(t, options.Silent)
+	require.Equal(t, 5*time.Second, options.Timeout)
+	require.Equal(t, []string{"a:b", "c:d"}, options.Headers)
+	require.Empty(t, options.Ignored)
+
+	var unknown struct {
+		Missing string `flag:"missing"`
+	}
+	err = flagSet.BindStruct(&unknown)
+	require.EqualError(t, err, "no such flag -missing for field Missing")
+
+	var mismatch struct {
+		Silent int `flag:"silent"`
+	}
+	err = flagSet.BindStruct(&mismatch)
+	require.EqualError(t, err, "cannot bind flag -silent of type bool to field Silent of type int")
+
+	err = flagSet.BindStruct(options)
+	require.NotNil(t, err, "non-pointer target should be rejected")
+	tearDown(t.Name())
+}
+
+func TestBindStructNumericConversion(t *testing.T) {
+	flagSet := NewFlagSet()
+	flagSet.DisableConfigLoading(true)
+	var large, small int
+	flagSet.IntVar(&large, "large", 0, "large value")
+	flagSet.IntVar(&small, "small", 0, "small value")
+	require.Nil(t, flagSet.ParseArgs([]string{"-large", "9007199254740993", "-small", "300"}))
+
+	var exact struct {
+		Large  int64   `flag:"large"`
+		Small  uint16  `flag:"small"`
+		Double float64 `flag:"small"`
+	}
+	require.Nil(t, flagSet.BindStruct(&exact))
+	require.Equal(t, int64(1<<53+1), exact.Large)
+	require.Equal(t, uint16(300), exact.Small)
+	require.Equal(t, float64(300), exact.Double)
+
+	var lossyFloat struct {
+		Large float64 `flag:"large"`
+	}
+	require.EqualError(t, flagSet.BindStruct(&lossyFloat), "cannot bind flag -large value 9007199254740993 to field Large of type float64 without loss")
+
+	var overflow struct {
+		Small int8 `flag:"small"`
+	}
+	require.EqualError(t, flagSet.BindStruct(&overflow), "cannot bind flag -small value 300 to field Small of type int8 without loss")
+	tearDown(t.Name())
+}
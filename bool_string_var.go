@@ -0,0 +1,77 @@
+package goflags
+
+import "fmt"
+
+// BoolString is the value of a flag used either as a toggle (-proxy)
+// or with a value (-proxy=http://127.0.0.1:8080).
+//
+// The value must be given with the -flag=value form: as for bool flags,
+// the argument following a bare -flag is not its value (-proxy http://host
+// enables the flag and leaves http://host as a positional argument).
+// The "true" and "false" values toggle the flag without a value.
+type BoolString struct {
+	// Enabled is true when the flag is given, with or without a value
+	Enabled bool
+	// Value is the value given with the -flag=value form, empty otherwise
+	Value string
+}
+
+func (b *BoolString) Set(value string) error {
+	switch value {
+	case "true":
+		*b = BoolString{Enabled: true}
+	case "false":
+		*b = BoolString{}
+	default:
+		*b = BoolString{Enabled: true, Value: value}
+	}
+	return nil
+}
+
+func (b *BoolString) String() string {
+	switch {
+	case !b.Enabled:
+		return "false"
+	case b.Value == "":
+		return "true"
+	default:
+		return b.Value
+	}
+}
+
+func (b *BoolString) IsBoolFlag() bool { return true }
+
+// BoolStringVar adds a bool or string flag with a longname
+func (flagSet *FlagSet) BoolStringVar(field *BoolString, long string, defaultValue string, usage string) *FlagData {
+	return flagSet.BoolStringVarP(field, long, "", defaultValue, usage)
+}
+
+// BoolStringVarP adds a bool or string flag with a shortname and longname.
+// The flag is enabled without value when given alone (ex: -proxy) and with
+// a value when given in the -flag=value form (ex: -proxy=http://127.0.0.1:8080).
+// The default value is parsed the same way, empty meaning disabled.
+func (flagSet *FlagSet) BoolStringVarP(field *BoolString, long, short string, defaultValue string, usage string) *FlagData {
+	if field == nil {
+		panic(fmt.Errorf("field cannot be nil for flag -%v", long))
+	}
+	*field = BoolString{}
+	if defaultValue != "" {
+		_ = field.Set(defaultValue)
+	}
+	flagData := &FlagData{
+		usage:        usage,
+		long:         long,
+		defaultValue: field.String(),
+	}
+	if !flagSet.checkDuplicateFlag(long, short) {
+		return flagData
+	}
+	if short != "" {
+		flagData.short = short
+		flagSet.CommandLine.Var(field, short, usage)
+		flagSet.setFlagKey(short, flagData)
+	}
+	flagSet.CommandLine.Var(field, long, usage)
+	flagSet.setFlagKey(long, flagData)
+	return flagData
+}
@@ -0,0 +1,73 @@
+package goflags
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBoolStringVar(t *testing.T) {
+	newFlagSet := func(proxy *BoolString, defaultValue string) *FlagSet {
+		flagSet := NewFlagSet()
+		flagSet.DisableConfigLoading(true)
+		flagSet.BoolStringVarP(proxy, "proxy", "p", defaultValue, "proxy to use (default system proxy)")
+		return flagSet
+	}
+
+	t.Run("bare", func(t *testing.T) {
+		var proxy BoolString
+		err := newFlagSet(&proxy, "").ParseArgs([]string{"-proxy"})
+		require.Nil(t, err)
+		require.Equal(t, BoolString{Enabled: true}, proxy)
+		tearDown(t.Name())
+	})
+
+	t.Run("valued", func(t *testing.T) {
+		var proxy BoolString
+		err := newFlagSet(&proxy, "").ParseArgs([]string{"-p=http://127.0.0.1:8080"})
+		require.Nil(t, err)
+		require.Equal(t, BoolString{Enabled: true, Value: "http://127.0.0.1:8080"}, proxy)
+		tearDown(t.Name())
+	})
+
+	t.Run("value after bare flag", func(t *testing.T) {
+		var proxy BoolString
+		flagSet := newFlagSet(&proxy, "")
+		err := flagSet.ParseArgs([]string{"-proxy", "http://127.0.0.1:8080"})
+		require.Nil(t, err)
+		require.Equal(t, BoolString{Enabled: true}, proxy)
+		require.Equal(t, []string{"http://127.0.0.1:8080"}, flagSet.Args(), "value should require the = form")
+		tearDown(t.Name())
+	})
+
+	t.Run("not given", func(t *testing.T) {
+		var proxy BoolString
+		err := newFlagSet(&proxy, "").ParseArgs(nil)
+		require.Nil(t, err)
+		require.Equal(t, BoolString{}, proxy)
+
+		err = newFlagSet(&proxy, "http://default:8080").ParseArgs([]string{"-proxy=false"})
+		require.Nil(t, err)
+		require.Equal(t, BoolString{}, proxy, "false should disable the default")
+		tearDown(t.Name())
+	})
+
+	t.Run("config", func(t *testing.T) {
+		var proxy BoolString
+		flagSet := newFlagSet(&proxy, "")
+		err := flagSet.MergeConfigReader(strings.NewReader("proxy: http://127.0.0.1:8080"), "yaml")
+		require.Nil(t, err)
+		require.Equal(t, BoolString{Enabled: true, Value: "http://127.0.0.1:8080"}, proxy)
+		tearDown(t.Name())
+	})
+
+	t.Run("usage", func(t *testing.T) {
+		var proxy BoolString
+		flag := newFlagSet(&proxy, "").CommandLine.Lookup("proxy")
+		displayType, _ := usageTypeAndDescription(flag, reflect.TypeOf(flag.Value))
+		require.Equal(t, "[=string]", displayType)
+		tearDown(t.Name())
+	})
+}
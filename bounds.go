@@ -0,0 +1,104 @@
+package goflags
+
+import (
+	"flag"
+	"fmt"
+	"time"
+)
+
+// validateBounds checks the values of flags with min/max bounds
+func (flagSet *FlagSet) validateBounds() error {
+	var err error
+	flagSet.flagKeys.forEach(func(key string, data *FlagData) {
+		if err != nil || key != data.canonicalName() {
+			return
+		}
+		err = flagSet.validateFlagBounds(key, data)
+	})
+	return err
+}
+
+// validateFlagBounds checks the value of a flag with min/max bounds
+func (flagSet *FlagSet) validateFlagBounds(key string, data *FlagData) error {
+	currentFlag := flagSet.CommandLine.Lookup(key)
+	if data.minDuration != nil || data.maxDuration != nil {
+		value, ok := flagValue(currentFlag).(time.Duration)
+		if !ok {
+			return fmt.Errorf("flag -%v is not a duration flag", key)
+		}
+		if err := checkBounds(key, value, data.minDuration, data.maxDuration); err != nil {
+			return err
+		}
+	}
+	if data.minInt != nil || data.maxInt != nil {
+		value, ok := flagValue(currentFlag).(int)
+		if !ok {
+			return fmt.Errorf("flag -%v is not an int flag", key)
+		}
+		return checkBounds(key, value, data.minInt, data.maxInt)
+	}
+	return nil
+}
+
+// validateSliceValues checks the values of string slice flags, combined
+// from all sources, with their MaxItems and ElementValidator options
+func (flagSet *FlagSet) validateSliceValues() error {
+	var err error
+	flagSet.flagKeys.forEach(func(key string, data *FlagData) {
+		if err != nil || key != data.canonicalName() {
+			return
+		}
+		err = flagSet.validateFlagSliceValues(key)
+	})
+	return err
+}
+
+// validateFlagSliceValues checks the values of a string slice flag
+// with its MaxItems and ElementValidator options
+func (flagSet *FlagSet) validateFlagSliceValues(key string) error {
+	currentFlag := flagSet.CommandLine.Lookup(key)
+	if currentFlag == nil {
+		return nil
+	}
+	stringSlice, ok := currentFlag.Value.(*StringSlice)
+	if !ok {
+		return nil
+	}
+	options := optionMap[stringSlice]
+	if options.MaxItems > 0 && len(*stringSlice) > options.MaxItems {
+		return newParseError(CategoryInvalid, key, stringSlice.String(), "flag -%v accepts at most %d values: got %d", key, options.MaxItems, len(*stringSlice))
+	}
+	if options.ElementValidator == nil {
+		return nil
+	}
+	for _, element := range *stringSlice {
+		if err := options.ElementValidator(element); err != nil {
+			return newParseError(CategoryInvalid, key, element, "flag -%v has invalid value %q: %v", key, element, err)
+		}
+	}
+	return nil
+}
+
+// checkBounds checks a flag value is within the (optional) min and max bounds
+func checkBounds[T int | time.Duration](name string, value T, min, max *T) error {
+	switch {
+	case min != nil && max != nil && (value < *min || value > *max):
+		return newParseError(CategoryInvalid, name, fmt.Sprint(value), "flag -%v must be between %v and %v: got %v", name, *min, *max, value)
+	case min != nil && value < *min:
+		return newParseError(CategoryInvalid, name, fmt.Sprint(value), "flag -%v must be at least %v: got %v", name, *min, value)
+	case max != nil && value > *max:
+		return newParseError(CategoryInvalid, name, fmt.Sprint(value), "flag -%v must be at most %v: got %v", name, *max, value)
+	}
+	return nil
+}
+
+// flagValue returns the typed value of a flag implementing flag.Getter
+func flagValue(currentFlag *flag.Flag) interface{} {
+	if currentFlag == nil {
+		return nil
+	}
+	if getter, ok := currentFlag.Value.(flag.Getter); ok {
+		return getter.Get()
+	}
+	return nil
+}
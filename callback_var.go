@@ -53,12 +53,18 @@ func (flagSet *FlagSet) CallbackVarP(callback CallBackFunc, long, short string,
 		field:        &callBackVar{Value: callback},
 		skipMarshal: true,
 	}
+	if flagSet.metaGroup {
+		flagData.group = metaGroup.name
+	}
+	if !flagSet.checkDuplicateFlag(long, short) {
+		return flagData
+	}
 	if short != "" {
 		flagData.short = short
 		flagSet.CommandLine.Var(flagData.field, short, usage)
-		flagSet.flagKeys.Set(short, flagData)
+		flagSet.setFlagKey(short, flagData)
 	}
 	flagSet.CommandLine.Var(flagData.field, long, usage)
-	flagSet.flagKeys.Set(long, flagData)
+	flagSet.setFlagKey(long, flagData)
 	return flagData
 }
@@ -0,0 +1,65 @@
+package goflags
+
+import (
+	"fmt"
+	"net"
+)
+
+type cidrValue struct {
+	value *net.IPNet
+}
+
+func (c *cidrValue) Set(s string) error {
+	_, ipNet, err := net.ParseCIDR(s)
+	if err != nil {
+		return fmt.Errorf("invalid cidr %q: expected format ip/prefix (ex: 10.0.0.0/8)", s)
+	}
+	*c.value = *ipNet
+	return nil
+}
+
+func (c *cidrValue) String() string {
+	if c.value == nil || c.value.IP == nil {
+		return ""
+	}
+	return c.value.String()
+}
+
+func (c *cidrValue) Get() any { return *c.value }
+
+func (c *cidrValue) displayType() string { return "string" }
+
+// CIDRVar adds a cidr flag with a longname
+func (flagSet *FlagSet) CIDRVar(field *net.IPNet, long string, defaultValue string, usage string) *FlagData {
+	return flagSet.CIDRVarP(field, long, "", defaultValue, usage)
+}
+
+// CIDRVarP adds a cidr flag with a shortname and longname.
+// The value is validated with net.ParseCIDR and stored as the network (ex: 10.0.0.1/8 => 10.0.0.0/8).
+func (flagSet *FlagSet) CIDRVarP(field *net.IPNet, long, short string, defaultValue string, usage string) *FlagData {
+	if field == nil {
+		panic(fmt.Errorf("field cannot be nil for flag -%v", long))
+	}
+	value := &cidrValue{value: field}
+	if defaultValue != "" {
+		if err := value.Set(defaultValue); err != nil {
+			panic(fmt.Errorf("failed to set default value for flag -%v: %v", long, err))
+		}
+	}
+	flagData := &FlagData{
+		usage:        usage,
+		long:         long,
+		defaultValue: defaultValue,
+	}
+	if !flagSet.checkDuplicateFlag(long, short) {
+		return flagData
+	}
+	if short != "" {
+		flagData.short = short
+		flagSet.CommandLine.Var(value, short, usage)
+		flagSet.setFlagKey(short, flagData)
+	}
+	flagSet.CommandLine.Var(value, long, usage)
+	flagSet.setFlagKey(long, flagData)
+	return flagData
+}
@@ -0,0 +1,55 @@
+package goflags
+
+import (
+	"net"
+	"os"
+	"testing"
+
+	permissionutil "github.com/projectdiscovery/utils/permission"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCIDRVar(t *testing.T) {
+	t.Run("valid-ipv4-cidr", func(t *testing.T) {
+		var cidr net.IPNet
+		flagSet := NewFlagSet()
+		flagSet.CreateGroup("Input", "Input",
+			flagSet.CIDRVarP(&cidr, "cidr", "c", "", "network range to scan"),
+		)
+		os.Args = []string{
+			os.Args[0],
+			"-cidr", "10.0.0.0/8",
+		}
+		err := flagSet.Parse()
+		assert.Nil(t, err)
+		assert.Equal(t, "10.0.0.0/8", cidr.String())
+		assert.True(t, cidr.Contains(net.ParseIP("10.1.2.3")))
+		tearDown(t.Name())
+	})
+
+	t.Run("valid-ipv6-cidr", func(t *testing.T) {
+		var cidr net.IPNet
+		flagSet := NewFlagSet()
+		flagSet.CIDRVar(&cidr, "cidr", "", "network range to scan")
+
+		err := os.WriteFile("test.yaml", []byte("cidr: 2001:db8::/32"), permissionutil.ConfigFilePermission)
+		require.Nil(t, err, "could not write temporary config")
+		defer os.Remove("test.yaml")
+
+		err = flagSet.MergeConfigFile("test.yaml")
+		require.Nil(t, err, "could not merge temporary config")
+		assert.Equal(t, "2001:db8::/32", cidr.String())
+		tearDown(t.Name())
+	})
+
+	t.Run("invalid-cidr", func(t *testing.T) {
+		for _, value := range []string{"10.0.0.0", "10.0.0.0/33", "300.0.0.0/8", "not-a-cidr"} {
+			var cidr net.IPNet
+			err := (&cidrValue{value: &cidr}).Set(value)
+			assert.NotNil(t, err)
+			assert.ErrorContains(t, err, "invalid cidr")
+		}
+		tearDown(t.Name())
+	})
+}
@@ -0,0 +1,93 @@
+package goflags
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+// command is a subcommand (ex: tool scan) with its own flags
+type command struct {
+	name        string
+	description string
+	flagSet     *FlagSet
+}
+
+// AddCommand adds a subcommand (ex: tool scan) returning its flagSet to
+// register the flags of the subcommand on. Parse dispatches the arguments
+// after the first positional argument matching the name to the subcommand,
+// once the flags before it are parsed and validated.
+func (flagSet *FlagSet) AddCommand(name, description string) *FlagSet {
+	if flagSet.lookupCommand(name) != nil {
+		panic(fmt.Errorf("command %q is already registered", name))
+	}
+	child := NewFlagSet()
+	child.CommandLine = flag.NewFlagSet(flagSet.CommandLine.Name()+" "+name, flagSet.CommandLine.ErrorHandling())
+	child.SetDescription(description)
+	child.parentName = flagSet.CommandLine.Name()
+	flagSet.commands = append(flagSet.commands, &command{name: name, description: description, flagSet: child})
+	return child
+}
+
+// Command returns the name of the subcommand dispatched by Parse,
+// empty if no subcommand was given.
+func (flagSet *FlagSet) Command() string {
+	return flagSet.parsedCommand
+}
+
+// lookupCommand returns the subcommand with the given name, nil if none
+func (flagSet *FlagSet) lookupCommand(name string) *command {
+	for _, cmd := range flagSet.commands {
+		if cmd.name == name {
+			return cmd
+		}
+	}
+	return nil
+}
+
+// parseCommand parses the arguments after the first positional
+// argument with the matching subcommand, if any
+func (flagSet *FlagSet) parseCommand() error {
+	flagSet.parsedCommand = ""
+	if len(flagSet.commands) == 0 || flagSet.NArg() == 0 {
+		return nil
+	}
+	cmd := flagSet.lookupCommand(flagSet.CommandLine.Arg(0))
+	if cmd == nil {
+		return nil
+	}
+	flagSet.parsedCommand = cmd.name
+
+	// subcommands share the output, help and config loading settings of the parent
+	if flagSet.output != nil && cmd.flagSet.output == nil {
+		cmd.flagSet.SetOutput(flagSet.output)
+	}
+	if cmd.flagSet.configFilePath == "" {
+		cmd.flagSet.SetConfigFilePath(flagSet.configFilePath)
+	}
+	if cmd.flagSet.configDir == "" {
+		cmd.flagSet.SetConfigDir(flagSet.configDir)
+	}
+	if flagSet.disableExitOnHelp {
+		cmd.flagSet.SetExitOnHelp(false)
+	}
+	if flagSet.disableConfigLoading {
+		cmd.flagSet.DisableConfigLoading(true)
+	}
+	return cmd.flagSet.ParseArgs(flagSet.CommandLine.Args()[1:])
+}
+
+// usageCommands writes the list of subcommands with their description
+func (flagSet *FlagSet) usageCommands(cliOutput io.Writer) {
+	if len(flagSet.commands) == 0 {
+		return
+	}
+	fmt.Fprintf(cliOutput, "Commands:\n")
+	writer := tabwriter.NewWriter(cliOutput, 0, 0, 2, ' ', 0)
+	for _, cmd := range flagSet.commands {
+		fmt.Fprintf(writer, "  %s\t%s\n", cmd.name, cmd.description)
+	}
+	writer.Flush()
+	fmt.Fprintf(cliOutput, "\n")
+}
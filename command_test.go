@@ -0,0 +1,104 @@
+package goflags
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddCommand(t *testing.T) {
+	newFlagSet := func() (*FlagSet, *bool, *string, *string) {
+		flagSet := NewFlagSet()
+		flagSet.DisableConfigLoading(true)
+		var verbose bool
+		var target, format string
+		flagSet.BoolVarP(&verbose, "verbose", "v", false, "verbose mode")
+
+		scan := flagSet.AddCommand("scan", "scan targets")
+		scan.StringVarP(&target, "target", "t", "", "target to scan")
+		report := flagSet.AddCommand("report", "generate a report")
+		report.StringVar(&format, "format", "json", "report format")
+		return flagSet, &verbose, &target, &format
+	}
+
+	t.Run("dispatch", func(t *testing.T) {
+		flagSet, verbose, target, format := newFlagSet()
+		err := flagSet.ParseArgs([]string{"-v", "scan", "-t", "example.com", "extra"})
+		require.Nil(t, err)
+		require.Equal(t, "scan", flagSet.Command())
+		require.True(t, *verbose)
+		require.Equal(t, "example.com", *target)
+		require.Equal(t, "json", *format, "report flags should keep their defaults")
+		tearDown(t.Name())
+	})
+
+	t.Run("no command", func(t *testing.T) {
+		flagSet, verbose, _, _ := newFlagSet()
+		err := flagSet.ParseArgs([]string{"-v"})
+		require.Nil(t, err)
+		require.Empty(t, flagSet.Command())
+		require.True(t, *verbose)
+		tearDown(t.Name())
+	})
+
+	t.Run("usage", func(t *testing.T) {
+		flagSet, _, _, _ := newFlagSet()
+		flagSet.SetExitOnHelp(false)
+		output := &bytes.Buffer{}
+		flagSet.SetOutput(output)
+		err := flagSet.ParseArgs([]string{"-h"})
+		require.Nil(t, err)
+		require.Contains(t, output.String(), "Commands:\n  scan    scan targets\n  report  generate a report\n")
+		tearDown(t.Name())
+	})
+
+	t.Run("command usage", func(t *testing.T) {
+		flagSet, _, _, _ := newFlagSet()
+		flagSet.SetExitOnHelp(false)
+		output := &bytes.Buffer{}
+		flagSet.SetOutput(output)
+		err := flagSet.ParseArgs([]string{"scan", "-h"})
+		require.Nil(t, err)
+		require.Equal(t, "scan", flagSet.Command())
+
+		usage := output.String()
+		require.Contains(t, usage, "scan targets")
+		require.Contains(t, usage, "Usage:\n  "+flagSet.CommandLine.Name()+" scan [flags]")
+		require.Contains(t, usage, "-t, -target string")
+		require.NotContains(t, usage, "-verbose", "global flags should not be rendered")
+		require.NotContains(t, usage, "-format", "other command flags should not be rendered")
+		require.NotContains(t, usage, "Commands:")
+		require.Contains(t, usage, "Global Flags:\n  run '"+flagSet.CommandLine.Name()+" -h' for the global flags")
+		tearDown(t.Name())
+	})
+
+	t.Run("config path", func(t *testing.T) {
+		homeDir := t.TempDir()
+		t.Setenv("HOME", homeDir)
+		t.Setenv("XDG_CONFIG_HOME", "")
+		configFilePath := filepath.Join(t.TempDir(), "config.yaml")
+
+		flagSet := NewFlagSet()
+		flagSet.SetConfigFilePath(configFilePath)
+		var target string
+		scan := flagSet.AddCommand("scan", "scan targets")
+		scan.StringVarP(&target, "target", "t", "", "target to scan")
+		err := flagSet.ParseArgs([]string{"scan", "-target", "x"})
+		require.Nil(t, err)
+		require.Equal(t, "x", target)
+		require.FileExists(t, configFilePath)
+
+		entries, err := os.ReadDir(homeDir)
+		require.Nil(t, err)
+		require.Empty(t, entries, "subcommand should not write a config under $HOME")
+		tearDown(t.Name())
+	})
+
+	require.Panics(t, func() {
+		flagSet, _, _, _ := newFlagSet()
+		flagSet.AddCommand("scan", "duplicate")
+	})
+}
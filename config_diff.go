@@ -0,0 +1,90 @@
+package goflags
+
+import "reflect"
+
+// ConfigDiffKind is the kind of change reported by a ConfigDiff
+type ConfigDiffKind string
+
+const (
+	// ConfigDiffAdded is used when a flag is only set by the second config
+	ConfigDiffAdded ConfigDiffKind = "added"
+	// ConfigDiffRemoved is used when a flag is only set by the first config
+	ConfigDiffRemoved ConfigDiffKind = "removed"
+	// ConfigDiffChanged is used when a flag is set to different values
+	ConfigDiffChanged ConfigDiffKind = "changed"
+)
+
+// ConfigDiff is a difference of a flag between two config files
+type ConfigDiff struct {
+	// Key is the canonical name of the flag
+	Key  string
+	Kind ConfigDiffKind
+	// Old and New are the decoded config items, nil if not set
+	Old interface{}
+	New interface{}
+}
+
+// DiffConfigs compares two config files read like MergeConfigFile (includes,
+// preprocessor, underscore keys and config keys) and returns the differences
+// of the flags and config-only flags in registration order. Keys not
+// matching any flag are ignored.
+func (flagSet *FlagSet) DiffConfigs(pathA, pathB string) ([]ConfigDiff, error) {
+	dataA, err := flagSet.readDiffConfig(pathA)
+	if err != nil {
+		return nil, err
+	}
+	dataB, err := flagSet.readDiffConfig(pathB)
+	if err != nil {
+		return nil, err
+	}
+
+	var diffs []ConfigDiff
+	compare := func(key string, names ...string) {
+		oldItem, oldOk := flagSet.lookupConfigItems(dataA, names)
+		newItem, newOk := flagSet.lookupConfigItems(dataB, names)
+		switch {
+		case oldOk && !newOk:
+			diffs = append(diffs, ConfigDiff{Key: key, Kind: ConfigDiffRemoved, Old: oldItem})
+		case !oldOk && newOk:
+			diffs = append(diffs, ConfigDiff{Key: key, Kind: ConfigDiffAdded, New: newItem})
+		case oldOk && newOk && !reflect.DeepEqual(oldItem, newItem):
+			diffs = append(diffs, ConfigDiff{Key: key, Kind: ConfigDiffChanged, Old: oldItem, New: newItem})
+		}
+	}
+	flagSet.flagKeys.forEach(func(key string, data *FlagData) {
+		if key != data.canonicalName() {
+			return
+		}
+		compare(key, key, data.short)
+	})
+	flagSet.configOnlyKeys.forEach(func(key string, data *FlagData) {
+		compare(key, key)
+	})
+	return diffs, nil
+}
+
+// readDiffConfig reads the config items of a file like MergeConfigFile,
+// running the config preprocessor if any
+func (flagSet *FlagSet) readDiffConfig(path string) (map[string]interface{}, error) {
+	data, err := flagSet.readConfigData(path, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := flagSet.preprocessConfig(data, path); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// lookupConfigItems returns the config item of the first of the flag names set
+func (flagSet *FlagSet) lookupConfigItems(data map[string]interface{}, names []string) (interface{}, bool) {
+	for _, name := range names {
+		if name == "" {
+			continue
+		}
+		if item, ok := flagSet.lookupConfigItem(data, name); ok {
+			return item, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,63 @@
+package goflags
+
+import (
+	"fmt"
+	"strconv"
+)
+
+type countValue int
+
+func newCountValue(val int, p *int) *countValue {
+	*p = val
+	return (*countValue)(p)
+}
+
+// Set increments the count each time the flag is given without value
+// (ex: -v -v -v => 3) or sets an explicit level (ex: -v=3 or config).
+func (c *countValue) Set(value string) error {
+	switch value {
+	case "true":
+		*c++
+		return nil
+	case "false":
+		*c = 0
+		return nil
+	}
+	level, err := strconv.Atoi(value)
+	if err != nil || level < 0 {
+		return fmt.Errorf("invalid count %q: expected a non-negative number", value)
+	}
+	*c = countValue(level)
+	return nil
+}
+
+func (c *countValue) String() string { return strconv.Itoa(int(*c)) }
+
+func (c *countValue) IsBoolFlag() bool { return true }
+
+// CountVar adds a count flag with a longname
+func (flagSet *FlagSet) CountVar(field *int, long string, defaultValue int, usage string) *FlagData {
+	return flagSet.CountVarP(field, long, "", defaultValue, usage)
+}
+
+// CountVarP adds a count flag with a shortname and longname.
+// The value is incremented each time the flag is repeated (ex: -v -v -v => 3)
+// and can be set to an explicit level (ex: -v=3 or "verbosity: 3" in config).
+func (flagSet *FlagSet) CountVarP(field *int, long, short string, defaultValue int, usage string) *FlagData {
+	flagData := &FlagData{
+		usage:        usage,
+		long:         long,
+		defaultValue: strconv.Itoa(defaultValue),
+	}
+	if !flagSet.checkDuplicateFlag(long, short) {
+		return flagData
+	}
+	if short != "" {
+		flagData.short = short
+		flagSet.CommandLine.Var(newCountValue(defaultValue, field), short, usage)
+		flagSet.setFlagKey(short, flagData)
+	}
+	flagSet.CommandLine.Var(newCountValue(defaultValue, field), long, usage)
+	flagSet.setFlagKey(long, flagData)
+	return flagData
+}
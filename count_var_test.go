@@ -0,0 +1,79 @@
+package goflags
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	permissionutil "github.com/projectdiscovery/utils/permission"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCountVar(t *testing.T) {
+	t.Run("repeated", func(t *testing.T) {
+		flagSet := NewFlagSet()
+		var verbosity int
+		flagSet.CountVarP(&verbosity, "verbose", "v", 0, "verbosity level")
+
+		os.Args = []string{
+			os.Args[0],
+			"-v", "-v", "-verbose",
+		}
+		err := flagSet.Parse()
+		require.Nil(t, err)
+		require.Equal(t, 3, verbosity)
+		tearDown(t.Name())
+	})
+
+	t.Run("explicit-level", func(t *testing.T) {
+		flagSet := NewFlagSet()
+		var verbosity int
+		flagSet.CountVarP(&verbosity, "verbose", "v", 0, "verbosity level")
+
+		os.Args = []string{
+			os.Args[0],
+			"-v=2", "-v",
+		}
+		err := flagSet.Parse()
+		require.Nil(t, err)
+		require.Equal(t, 3, verbosity)
+		tearDown(t.Name())
+	})
+
+	t.Run("config", func(t *testing.T) {
+		flagSet := NewFlagSet()
+		var verbosity int
+		flagSet.CountVarP(&verbosity, "verbosity", "v", 0, "verbosity level")
+
+		err := os.WriteFile("test.yaml", []byte("verbosity: 3"), permissionutil.ConfigFilePermission)
+		require.Nil(t, err, "could not write temporary config")
+		defer os.Remove("test.yaml")
+
+		err = flagSet.MergeConfigFile("test.yaml")
+		require.Nil(t, err, "could not merge temporary config")
+		require.Equal(t, 3, verbosity)
+		tearDown(t.Name())
+	})
+
+	t.Run("usage", func(t *testing.T) {
+		flagSet := NewFlagSet()
+		var verbosity int
+		flagSet.CountVarP(&verbosity, "verbose", "v", 0, "verbosity level")
+
+		output := &bytes.Buffer{}
+		flagSet.CommandLine.SetOutput(output)
+		os.Args = []string{
+			os.Args[0],
+			"-h",
+		}
+		flagSet.usageFunc()
+		require.Contains(t, output.String(), "-v, -verbose count  verbosity level (repeatable)")
+		tearDown(t.Name())
+	})
+
+	t.Run("invalid-level", func(t *testing.T) {
+		var verbosity int
+		err := newCountValue(0, &verbosity).Set("-1")
+		require.NotNil(t, err)
+	})
+}
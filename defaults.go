@@ -0,0 +1,57 @@
+package goflags
+
+import (
+	"io"
+	"reflect"
+
+	"gopkg.in/yaml.v3"
+)
+
+// PrintDefaults writes the registered default value of each flag as YAML,
+// keyed by flag name in registration order.
+func (flagSet *FlagSet) PrintDefaults(w io.Writer) error {
+	defaults := &yaml.Node{Kind: yaml.MappingNode}
+	flagSet.flagKeys.forEach(func(key string, data *FlagData) {
+		if key != data.canonicalName() || data.skipMarshal || flagSet.isHidden(data) {
+			return
+		}
+		var valueNode *yaml.Node
+		switch defaultValue := data.defaultValue.(type) {
+		case []string:
+			valueNode = sequenceNode(defaultValue)
+		case StringSlice:
+			valueNode = sequenceNode(defaultValue)
+		default:
+			valueNode = &yaml.Node{Kind: yaml.ScalarNode}
+			if currentFlag := flagSet.CommandLine.Lookup(key); currentFlag != nil {
+				valueNode.Value = currentFlag.DefValue
+				// keep string defaults as strings (ex: "10")
+				if reflect.TypeOf(currentFlag.Value).String() == "*flag.stringValue" {
+					valueNode.Tag = "!!str"
+				}
+			}
+			if valueNode.Value == "" {
+				valueNode.Style = yaml.DoubleQuotedStyle
+			}
+		}
+		if data.secret {
+			valueNode = &yaml.Node{Kind: yaml.ScalarNode, Value: secretMask}
+		}
+		defaults.Content = append(defaults.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, valueNode)
+	})
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(defaults); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+func sequenceNode(values []string) *yaml.Node {
+	node := &yaml.Node{Kind: yaml.SequenceNode, Style: yaml.FlowStyle}
+	for _, value := range values {
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: value})
+	}
+	return node
+}
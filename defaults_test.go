@@ -0,0 +1,57 @@
+package goflags
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestPrintDefaults(t *testing.T) {
+	flagSet := NewFlagSet()
+	var (
+		stringData   string
+		emptyData    string
+		numericData  string
+		intData      int
+		boolData     bool
+		durationData time.Duration
+		sliceData    StringSlice
+		apiKey       string
+	)
+	flagSet.StringVarP(&stringData, "string-value", "sv", "test", "String value example")
+	flagSet.StringVar(&emptyData, "empty-value", "", "Empty value example")
+	flagSet.StringVar(&numericData, "numeric-value", "10", "Numeric string value example")
+	flagSet.IntVar(&intData, "int-value", 10, "Int value example")
+	flagSet.BoolVar(&boolData, "bool-value", true, "Bool value example")
+	flagSet.DurationVar(&durationData, "duration-value", time.Minute, "Duration value example")
+	flagSet.StringSliceVar(&sliceData, "slice-value", []string{"a", "b"}, "Slice value example", StringSliceOptions)
+	flagSet.StringVar(&apiKey, "api-key", "secret-value", "API key example").Secret()
+
+	// defaults are printed even after values are changed
+	require.Nil(t, flagSet.Set("int-value", "20"))
+
+	output := &bytes.Buffer{}
+	err := flagSet.PrintDefaults(output)
+	require.Nil(t, err)
+
+	expected := `string-value: test
+empty-value: ""
+numeric-value: "10"
+int-value: 10
+bool-value: true
+duration-value: 1m0s
+slice-value: [a, b]
+api-key: '********'
+`
+	require.Equal(t, expected, output.String())
+
+	var defaults map[string]interface{}
+	require.Nil(t, yaml.Unmarshal(output.Bytes(), &defaults), "could not parse defaults as yaml")
+	require.Equal(t, 10, defaults["int-value"])
+	require.Equal(t, "10", defaults["numeric-value"])
+	require.Equal(t, []interface{}{"a", "b"}, defaults["slice-value"])
+	tearDown(t.Name())
+}
@@ -2,6 +2,7 @@ package goflags
 
 import (
 	"errors"
+	"os"
 	"time"
 	timeutil "github.com/projectdiscovery/utils/time"
 )
@@ -40,12 +41,40 @@ func (flagSet *FlagSet) DurationVarP(field *time.Duration, long, short string, d
 		long:         long,
 		defaultValue: defaultValue,
 	}
+	if !flagSet.checkDuplicateFlag(long, short) {
+		return flagData
+	}
 	if short != "" {
 		flagData.short = short
 		flagSet.CommandLine.Var(newDurationValue(defaultValue, field), short, usage)
-		flagSet.flagKeys.Set(short, flagData)
+		flagSet.setFlagKey(short, flagData)
 	}
 	flagSet.CommandLine.Var(newDurationValue(defaultValue, field), long, usage)
-	flagSet.flagKeys.Set(long, flagData)
+	flagSet.setFlagKey(long, flagData)
+	return flagData
+}
+
+// DurationVarEnv adds a duration flag with a shortname and longname with a default value read from env variable
+// with a default value fallback (used as well when the env value is not a valid duration)
+func (flagSet *FlagSet) DurationVarEnv(field *time.Duration, long, short string, defaultValue time.Duration, envName, usage string) *FlagData {
+	if envValue, exists := os.LookupEnv(envName); exists {
+		if value, err := timeutil.ParseDuration(envValue); err == nil {
+			defaultValue = value
+		}
+	}
+	return flagSet.DurationVarP(field, long, short, defaultValue, usage)
+}
+
+// MinDuration sets the minimum allowed duration for a duration flag,
+// validated during Parse for values from the command line and config.
+func (flagData *FlagData) MinDuration(min time.Duration) *FlagData {
+	flagData.minDuration = &min
+	return flagData
+}
+
+// MaxDuration sets the maximum allowed duration for a duration flag,
+// validated during Parse for values from the command line and config.
+func (flagData *FlagData) MaxDuration(max time.Duration) *FlagData {
+	flagData.maxDuration = &max
 	return flagData
 }
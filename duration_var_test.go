@@ -5,7 +5,9 @@ import (
 	"testing"
 	"time"
 
+	permissionutil "github.com/projectdiscovery/utils/permission"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestDurationVar(t *testing.T) {
@@ -41,3 +43,42 @@ func TestDurationVar(t *testing.T) {
 		tearDown(t.Name())
 	})
 }
+
+func TestDurationVarBounds(t *testing.T) {
+	parse := func(t *testing.T, args ...string) (time.Duration, error) {
+		var timeout time.Duration
+		flagSet := NewFlagSet()
+		flagSet.SetConfigFilePath("test.yaml")
+		flagSet.DurationVarP(&timeout, "timeout", "tm", 10*time.Second, "timeout for the process").
+			MinDuration(time.Second).
+			MaxDuration(time.Minute)
+		err := flagSet.ParseArgs(args)
+		tearDown(t.Name())
+		return timeout, err
+	}
+	err := os.WriteFile("test.yaml", []byte("timeout: 2m"), permissionutil.ConfigFilePermission)
+	require.Nil(t, err, "could not write temporary config")
+	defer os.Remove("test.yaml")
+
+	t.Run("in-range", func(t *testing.T) {
+		timeout, err := parse(t, "-tm", "30s")
+		require.Nil(t, err)
+		require.Equal(t, 30*time.Second, timeout)
+	})
+
+	t.Run("boundary", func(t *testing.T) {
+		timeout, err := parse(t, "-tm", "1m")
+		require.Nil(t, err)
+		require.Equal(t, time.Minute, timeout)
+	})
+
+	t.Run("below-min", func(t *testing.T) {
+		_, err := parse(t, "-tm", "500ms")
+		require.EqualError(t, err, "flag -timeout must be between 1s and 1m0s: got 500ms")
+	})
+
+	t.Run("above-max-config", func(t *testing.T) {
+		_, err := parse(t)
+		require.EqualError(t, err, "flag -timeout must be between 1s and 1m0s: got 2m0s")
+	})
+}
@@ -113,12 +113,15 @@ func (flagSet *FlagSet) DynamicVarP(field interface{}, long, short string, defau
 		long:         long,
 		defaultValue: defaultValue,
 	}
+	if !flagSet.checkDuplicateFlag(long, short) {
+		return flagData
+	}
 	if short != "" {
 		flagData.short = short
 		flagSet.CommandLine.Var(&dynamicFlag, short, usage)
-		flagSet.flagKeys.Set(short, flagData)
+		flagSet.setFlagKey(short, flagData)
 	}
 	flagSet.CommandLine.Var(&dynamicFlag, long, usage)
-	flagSet.flagKeys.Set(long, flagData)
+	flagSet.setFlagKey(long, flagData)
 	return flagData
 }
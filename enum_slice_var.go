@@ -2,6 +2,7 @@ package goflags
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 )
 
@@ -17,14 +18,20 @@ func (e *EnumSliceVar) String() string {
 	return ""
 }
 
+func (e *EnumSliceVar) Get() any { return append([]string(nil), *e.value...) }
+
 func (e *EnumSliceVar) Set(value string) error {
 	values := strings.Split(value, ",")
+	// report all the invalid values at once
+	var invalidValues []string
 	for _, v := range values {
-		_, ok := e.allowedTypes[v]
-		if !ok {
-			return fmt.Errorf("allowed values are %v", e.allowedTypes.String())
+		if _, ok := e.allowedTypes[v]; !ok {
+			invalidValues = append(invalidValues, strconv.Quote(v))
 		}
 	}
+	if len(invalidValues) > 0 {
+		return fmt.Errorf("invalid values %s: allowed values are %v", strings.Join(invalidValues, ", "), e.allowedTypes.String())
+	}
 	*e.value = values
 	return nil
 }
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 var enumSliceData []string
@@ -58,4 +59,14 @@ func TestEnumSliceVar(t *testing.T) {
 		t.Fatalf("process ran with err %v, want exit error", err)
 		tearDown(t.Name())
 	})
+	t.Run("Test with mixed valid and invalid values", func(t *testing.T) {
+		var values []string
+		enumSlice := &EnumSliceVar{allowedTypes: AllowdTypes{"type1": Type1}, value: &values}
+		err := enumSlice.Set("type1,bad1,type1,bad2")
+		require.NotNil(t, err)
+		require.Contains(t, err.Error(), `invalid values "bad1", "bad2"`)
+		require.Contains(t, err.Error(), "allowed values are type1")
+		require.Nil(t, values, "values were set with invalid entries")
+		tearDown(t.Name())
+	})
 }
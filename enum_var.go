@@ -1,7 +1,9 @@
 package goflags
 
 import (
+	"flag"
 	"fmt"
+	"sort"
 	"strings"
 )
 
@@ -21,6 +23,42 @@ func (a AllowdTypes) String() string {
 	return strings.TrimSuffix(str, ", ")
 }
 
+// key returns the allowed value of an enum variable, the first
+// in sorted order if several values map to the same variable
+func (a AllowdTypes) key(variable EnumVariable) (string, bool) {
+	var keys []string
+	for k, v := range a {
+		if v == variable {
+			keys = append(keys, k)
+		}
+	}
+	if len(keys) == 0 {
+		return "", false
+	}
+	sort.Strings(keys)
+	return keys[0], true
+}
+
+// enumAllowedValues returns the sorted allowed values of
+// an enum or enum slice flag value, nil for other values
+func enumAllowedValues(value flag.Value) []string {
+	var allowedTypes AllowdTypes
+	switch enum := value.(type) {
+	case *EnumVar:
+		allowedTypes = enum.allowedTypes
+	case *EnumSliceVar:
+		allowedTypes = enum.allowedTypes
+	default:
+		return nil
+	}
+	values := make([]string, 0, len(allowedTypes))
+	for k := range allowedTypes {
+		values = append(values, k)
+	}
+	sort.Strings(values)
+	return values
+}
+
 type EnumVar struct {
 	allowedTypes AllowdTypes
 	value        *string
@@ -33,6 +71,8 @@ func (e *EnumVar) String() string {
 	return ""
 }
 
+func (e *EnumVar) Get() any { return *e.value }
+
 func (e *EnumVar) Set(value string) error {
 	_, ok := e.allowedTypes[value]
 	if !ok {
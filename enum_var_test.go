@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 var enumString string
@@ -50,3 +51,17 @@ func TestFailEnumVar(t *testing.T) {
 	t.Fatalf("process ran with err %v, want exit error", err)
 	tearDown(t.Name())
 }
+
+func TestEnumVarInvalidDefault(t *testing.T) {
+	flagSet := NewFlagSet()
+	var value string
+	require.PanicsWithError(t, "invalid default value 0 for flag -enum: allowed values are type1", func() {
+		flagSet.EnumVar(&value, "enum", Nil, "enum", AllowdTypes{"type1": Type1})
+	})
+
+	var values []string
+	require.PanicsWithError(t, "invalid default value 2 for flag -enum-slice: allowed values are type1", func() {
+		flagSet.EnumSliceVar(&values, "enum-slice", []EnumVariable{Type1, Type2}, "enum slice", AllowdTypes{"type1": Type1})
+	})
+	tearDown(t.Name())
+}
@@ -0,0 +1,68 @@
+package goflags
+
+import (
+	"fmt"
+	"time"
+)
+
+// GetString returns the value of a string flag (including url and enum
+// flags) by its short, long or alias name
+func (flagSet *FlagSet) GetString(name string) (string, error) {
+	return getFlagValue[string](flagSet, name, "a string")
+}
+
+// GetInt returns the value of an int flag by its short, long or alias name
+func (flagSet *FlagSet) GetInt(name string) (int, error) {
+	return getFlagValue[int](flagSet, name, "an int")
+}
+
+// GetBool returns the value of a bool flag by its short, long or alias name
+func (flagSet *FlagSet) GetBool(name string) (bool, error) {
+	return getFlagValue[bool](flagSet, name, "a bool")
+}
+
+// GetDuration returns the value of a duration flag by its short, long or alias name
+func (flagSet *FlagSet) GetDuration(name string) (time.Duration, error) {
+	return getFlagValue[time.Duration](flagSet, name, "a duration")
+}
+
+// GetStringSlice returns a copy of the values of a string slice flag
+// by its short, long or alias name
+func (flagSet *FlagSet) GetStringSlice(name string) ([]string, error) {
+	currentFlag := flagSet.Lookup(name)
+	if currentFlag == nil {
+		return nil, fmt.Errorf("no such flag -%v", name)
+	}
+	stringSlice, ok := currentFlag.Value.(*StringSlice)
+	if !ok {
+		return nil, fmt.Errorf("flag -%v is not a string slice flag", name)
+	}
+	return append([]string{}, *stringSlice...), nil
+}
+
+// EnumValues returns the sorted allowed values of an enum or enum slice
+// flag by its short, long or alias name, false for other flags
+func (flagSet *FlagSet) EnumValues(name string) ([]string, bool) {
+	currentFlag := flagSet.Lookup(name)
+	if currentFlag == nil {
+		return nil, false
+	}
+	values := enumAllowedValues(currentFlag.Value)
+	return values, values != nil
+}
+
+// getFlagValue returns the typed value of a flag implementing flag.Getter.
+// Values of the custom flag types are returned as their go value (ex: []int
+// for port range flags, net.IPNet for cidr flags).
+func getFlagValue[T any](flagSet *FlagSet, name, typeName string) (T, error) {
+	var zero T
+	currentFlag := flagSet.Lookup(name)
+	if currentFlag == nil {
+		return zero, fmt.Errorf("no such flag -%v", name)
+	}
+	value, ok := flagValue(currentFlag).(T)
+	if !ok {
+		return zero, fmt.Errorf("flag -%v is not %s flag", name, typeName)
+	}
+	return value, nil
+}
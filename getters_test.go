@@ -0,0 +1,114 @@
+package goflags
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTypedGetters(t *testing.T) {
+	flagSet := NewFlagSet()
+	flagSet.DisableConfigLoading(true)
+	var stringData string
+	var intData int
+	var boolData bool
+	var durationData time.Duration
+	var sliceData StringSlice
+	flagSet.StringVarP(&stringData, "string-value", "sv", "", "String value example").Alias("str")
+	flagSet.IntVarP(&intData, "int-value", "iv", 0, "Int value example")
+	flagSet.BoolVarP(&boolData, "bool-value", "bv", false, "Bool value example")
+	flagSet.DurationVarP(&durationData, "duration-value", "dv", time.Second, "Duration value example")
+	flagSet.StringSliceVarP(&sliceData, "slice-value", "sl", nil, "String slice value example", CommaSeparatedStringSliceOptions)
+
+	err := flagSet.ParseArgs([]string{"-str", "test", "-iv", "10", "-bool-value", "-dv", "1m", "-sl", "a,b"})
+	require.Nil(t, err)
+
+	stringValue, err := flagSet.GetString("sv")
+	require.Nil(t, err)
+	require.Equal(t, "test", stringValue)
+
+	intValue, err := flagSet.GetInt("int-value")
+	require.Nil(t, err)
+	require.Equal(t, 10, intValue)
+
+	boolValue, err := flagSet.GetBool("bv")
+	require.Nil(t, err)
+	require.True(t, boolValue)
+
+	durationValue, err := flagSet.GetDuration("duration-value")
+	require.Nil(t, err)
+	require.Equal(t, time.Minute, durationValue)
+
+	sliceValue, err := flagSet.GetStringSlice("sl")
+	require.Nil(t, err)
+	require.Equal(t, []string{"a", "b"}, sliceValue)
+
+	_, err = flagSet.GetInt("string-value")
+	require.EqualError(t, err, "flag -string-value is not an int flag")
+	_, err = flagSet.GetStringSlice("bool-value")
+	require.EqualError(t, err, "flag -bool-value is not a string slice flag")
+	_, err = flagSet.GetString("missing")
+	require.EqualError(t, err, "no such flag -missing")
+	tearDown(t.Name())
+}
+
+func TestEnumValues(t *testing.T) {
+	flagSet := NewFlagSet()
+	var severity string
+	var protocols []string
+	var output string
+	flagSet.EnumVarP(&severity, "severity", "s", Type1, "severity", AllowdTypes{"low": Type1, "high": Type2, "critical": Nil})
+	flagSet.EnumSliceVar(&protocols, "protocol", []EnumVariable{Type1}, "protocols", AllowdTypes{"tcp": Type1, "udp": Type2})
+	flagSet.StringVar(&output, "output", "", "output file")
+
+	values, ok := flagSet.EnumValues("s")
+	require.True(t, ok)
+	require.Equal(t, []string{"critical", "high", "low"}, values)
+
+	values, ok = flagSet.EnumValues("protocol")
+	require.True(t, ok)
+	require.Equal(t, []string{"tcp", "udp"}, values)
+
+	_, ok = flagSet.EnumValues("output")
+	require.False(t, ok, "string flag has no enum values")
+	_, ok = flagSet.EnumValues("missing")
+	require.False(t, ok)
+	tearDown(t.Name())
+}
+
+func TestTypedGettersCustomTypes(t *testing.T) {
+	flagSet := NewFlagSet()
+	flagSet.DisableConfigLoading(true)
+	var proxy, severity string
+	var ports []int
+	var network net.IPNet
+	flagSet.URLVar(&proxy, "proxy", "", "proxy url")
+	flagSet.EnumVar(&severity, "severity", Type1, "severity", AllowdTypes{"low": Type1, "high": Type2})
+	flagSet.PortRangeVar(&ports, "ports", "80", "ports")
+	flagSet.CIDRVar(&network, "network", "", "network")
+
+	err := flagSet.ParseArgs([]string{"-proxy", "http://127.0.0.1:8080", "-severity", "high", "-ports", "8000-8002", "-network", "10.0.0.1/8"})
+	require.Nil(t, err)
+
+	proxyValue, err := flagSet.GetString("proxy")
+	require.Nil(t, err)
+	require.Equal(t, "http://127.0.0.1:8080", proxyValue)
+
+	severityValue, err := flagSet.GetString("severity")
+	require.Nil(t, err)
+	require.Equal(t, "high", severityValue)
+
+	portsValue, err := getFlagValue[[]int](flagSet, "ports", "a port range")
+	require.Nil(t, err)
+	require.Equal(t, []int{8000, 8001, 8002}, portsValue)
+
+	networkValue, err := getFlagValue[net.IPNet](flagSet, "network", "a cidr")
+	require.Nil(t, err)
+	require.Equal(t, "10.0.0.0/8", networkValue.String())
+
+	_, err = flagSet.GetString("network")
+	require.EqualError(t, err, "flag -network is not a string flag")
+	tearDown(t.Name())
+}
@@ -3,6 +3,7 @@ module github.com/projectdiscovery/goflags
 go 1.20
 
 require (
+	github.com/BurntSushi/toml v1.4.0
 	github.com/cnf/structhash v0.0.0-20201127153200-e1b16c1ebc08
 	github.com/pkg/errors v0.9.1
 	github.com/stretchr/testify v1.8.4
@@ -2,6 +2,7 @@ package goflags
 
 import (
 	"bytes"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
@@ -9,14 +10,17 @@ import (
 	"path"
 	"path/filepath"
 	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 	"text/tabwriter"
 	"time"
 
+	"github.com/BurntSushi/toml"
 	"github.com/cnf/structhash"
 	fileutil "github.com/projectdiscovery/utils/file"
 	permissionutil "github.com/projectdiscovery/utils/permission"
+	sliceutil "github.com/projectdiscovery/utils/slice"
 	"golang.org/x/exp/maps"
 	"gopkg.in/yaml.v3"
 )
@@ -31,10 +35,56 @@ type FlagSet struct {
 	groups         []groupData
 	CommandLine    *flag.FlagSet
 	configFilePath string
+	configDir      string
 
 	// OtherOptionsGroupName is the name for all flags not in a group
 	OtherOptionsGroupName string
 	configOnlyKeys        InsertionOrderedMap
+	// configFlags contains the flags set by merged config files
+	configFlags       map[*FlagData]struct{}
+	allowExperimental bool
+	equalLengthFlags  [][2]string
+	traceWriter       io.Writer
+	configStrict      bool
+	// disableConfigLoading skips reading and creating the default config on Parse
+	disableConfigLoading bool
+	onParsed             []func() error
+	computedDefaults     []func(*FlagSet) error
+	validateFlagNames    bool
+	// sortFlags orders flags alphabetically within groups in usage and docs
+	sortFlags bool
+	// disableBuiltinHelp passes -h and -help to the caller instead of printing usage
+	disableBuiltinHelp bool
+	// metaGroup renders the callback flags without group in a trailing meta group
+	metaGroup     bool
+	versionFormat string
+	// disableExitOnHelp returns from parse after printing usage for -h
+	disableExitOnHelp bool
+	// output receives all goflags output (usage, warnings, version)
+	output io.Writer
+	// commands are the subcommands dispatched on parse
+	commands      []*command
+	parsedCommand string
+	// parentName is the name of the parent of a subcommand, shown in usage
+	parentName string
+	// configExtras are the config items of the last merge not matching any flag
+	configExtras map[string]interface{}
+	// configEnvExpansion expands environment variables in config values
+	configEnvExpansion bool
+	configEnvStrict    bool
+	// suggestFlags returns unknown command line flags as errors with a suggestion
+	suggestFlags  bool
+	helpRequested bool
+	// args are the arguments being parsed (without program name), used
+	// by usage to detect help requests. os.Args is used when empty.
+	args []string
+	// allowAbbreviations resolves unambiguous prefixes of long flag names
+	allowAbbreviations bool
+	// configPreprocessor is called with the items of each config before they are applied
+	configPreprocessor func(map[string]interface{}) error
+	// deferFlagCollisions records duplicate names for Validate instead of panicking
+	deferFlagCollisions bool
+	flagCollisions      []error
 }
 
 type groupData struct {
@@ -42,6 +92,14 @@ type groupData struct {
 	description string
 }
 
+// FlagGroup contains a group name, description and the
+// canonical names of its flags in registration order
+type FlagGroup struct {
+	Name        string
+	Description string
+	Flags       []string
+}
+
 type FlagData struct {
 	usage        string
 	short        string
@@ -50,6 +108,32 @@ type FlagData struct {
 	defaultValue interface{}
 	skipMarshal  bool
 	field        flag.Value
+	experimental bool
+	configKey    string
+	secret       bool
+	minDuration  *time.Duration
+	maxDuration  *time.Duration
+	minInt       *int
+	maxInt       *int
+	required     bool
+	deprecated   bool
+	since        string
+	aliases      []string
+	// defaultConfigKey is the config key setting the default value of the flag
+	defaultConfigKey string
+	// configCommaForm writes slice defaults in comma form in generated configs
+	configCommaForm bool
+	// configSeparator joins slice defaults written in comma form
+	configSeparator rune
+	// fromFile reads the value of the flag from the file path it is set to
+	fromFile bool
+	// configComment replaces the usage as comment in generated configs
+	configComment string
+	// registeredDefValue is the registered default of the flag, kept by
+	// DefaultFromConfig when it replaces the default, restored by Reset
+	registeredDefValue *string
+	// flagSet is the flag set the flag is registered in, not part of the hash
+	flagSet *FlagSet `hash:"-"`
 }
 
 // Group sets the group for a flag data
@@ -57,6 +141,97 @@ func (flagData *FlagData) Group(name string) {
 	flagData.group = name
 }
 
+// Experimental marks the flag as experimental. Experimental flags are hidden
+// from usage and cannot be set unless enabled with FlagSet.SetAllowExperimental.
+func (flagData *FlagData) Experimental() *FlagData {
+	flagData.experimental = true
+	return flagData
+}
+
+// Secret marks the flag as sensitive (ex: api keys). Its value is parsed
+// normally but never written to generated config files, usage or dumps.
+func (flagData *FlagData) Secret() *FlagData {
+	flagData.secret = true
+	return flagData
+}
+
+// Required marks the flag as required. Parse returns a ParseError with
+// the CategoryRequired category if it is not set on the command line or config.
+func (flagData *FlagData) Required() *FlagData {
+	flagData.required = true
+	return flagData
+}
+
+// Deprecated marks the flag as deprecated. The flag is parsed and shown in
+// usage like other flags, the state is reported in SchemaJSON output.
+func (flagData *FlagData) Deprecated() *FlagData {
+	flagData.deprecated = true
+	return flagData
+}
+
+// Since sets the version the flag was introduced in (ex: v1.4.0).
+// It is shown in SchemaJSON and MarkdownDoc output but not in usage.
+func (flagData *FlagData) Since(version string) *FlagData {
+	flagData.since = version
+	return flagData
+}
+
+// Alias adds extra names for the flag (ex: a deprecated name), usable on the
+// command line, in config files and with Set, Lookup and the getters.
+// Aliases are not shown in usage. It panics if a name is already registered.
+func (flagData *FlagData) Alias(names ...string) *FlagData {
+	flagData.aliases = append(flagData.aliases, names...)
+	if flagData.flagSet != nil {
+		for _, name := range names {
+			flagData.flagSet.registerAlias(flagData, name)
+		}
+	}
+	return flagData
+}
+
+// ConfigComment sets the comment written above the flag in generated config
+// files instead of its usage. Each line of a multi-line comment is prefixed with #.
+func (flagData *FlagData) ConfigComment(comment string) *FlagData {
+	flagData.configComment = comment
+	return flagData
+}
+
+// FromFile reads the value of the flag from the file path it is set to
+// (ex: -api-key /run/secrets/key), with surrounding whitespace trimmed.
+// Parse returns a ParseError if the file cannot be read. It is meant for
+// single value flags and can be combined with Secret.
+func (flagData *FlagData) FromFile() *FlagData {
+	flagData.fromFile = true
+	return flagData
+}
+
+// DefaultFromConfig sets a config key (ex: defaults.timeout, a dotted path for
+// nested keys) providing the default value of the flag. Precedence is: command
+// line, flag name (or ConfigKey) in config, this key, registered default.
+func (flagData *FlagData) DefaultFromConfig(key string) *FlagData {
+	flagData.defaultConfigKey = key
+	return flagData
+}
+
+// secretMask replaces secret flag values in usage and dumps
+const secretMask = "********"
+
+// ConfigKey sets the key looked up for the flag in config files instead of
+// the flag name (ex: legacy key names). The flag name is still accepted,
+// the override key takes precedence if both are present.
+func (flagData *FlagData) ConfigKey(key string) *FlagData {
+	flagData.configKey = key
+	return flagData
+}
+
+// canonicalName returns the name all aliases of a flag resolve to
+func (flagData *FlagData) canonicalName() string {
+	if flagData.long != "" {
+		return flagData.long
+	}
+	return flagData.short
+}
+
 // NewFlagSet creates a new flagSet structure for the application
 func NewFlagSet() *FlagSet {
 	flag.CommandLine.ErrorHandling()
@@ -65,7 +240,175 @@ func NewFlagSet() *FlagSet {
 		OtherOptionsGroupName: "other options",
 		CommandLine:           flag.NewFlagSet(os.Args[0], flag.ExitOnError),
 		configOnlyKeys:        newInsertionOrderedMap(),
+		configFlags:           make(map[*FlagData]struct{}),
+	}
+}
+
+// Clone returns a new flagSet with the same flags, groups and options but
+// with independent parse state (provided flags, config sources).
+//
+// NOTE: the flag values are shared with the original flagSet, parsing the
+// clone writes to the same variables bound at registration.
+func (flagSet *FlagSet) Clone() *FlagSet {
+	clone := &FlagSet{
+		CaseSensitive:         flagSet.CaseSensitive,
+		Marshal:               flagSet.Marshal,
+		description:           flagSet.description,
+		customHelpText:        flagSet.customHelpText,
+		flagKeys:              newInsertionOrderedMap(),
+		groups:                append([]groupData(nil), flagSet.groups...),
+		CommandLine:           copyCommandLine(flagSet.CommandLine),
+		configFilePath:        flagSet.configFilePath,
+		configDir:             flagSet.configDir,
+		OtherOptionsGroupName: flagSet.OtherOptionsGroupName,
+		configOnlyKeys:        newInsertionOrderedMap(),
+		configFlags:           make(map[*FlagData]struct{}),
+		allowExperimental:     flagSet.allowExperimental,
+		equalLengthFlags:      append([][2]string(nil), flagSet.equalLengthFlags...),
+		traceWriter:           flagSet.traceWriter,
+		configStrict:          flagSet.configStrict,
+		disableConfigLoading:  flagSet.disableConfigLoading,
+		onParsed:              append([]func() error(nil), flagSet.onParsed...),
+		computedDefaults:      append([]func(*FlagSet) error(nil), flagSet.computedDefaults...),
+		validateFlagNames:     flagSet.validateFlagNames,
+		sortFlags:             flagSet.sortFlags,
+		disableBuiltinHelp:    flagSet.disableBuiltinHelp,
+		disableExitOnHelp:     flagSet.disableExitOnHelp,
+		metaGroup:             flagSet.metaGroup,
+		versionFormat:         flagSet.versionFormat,
+		suggestFlags:          flagSet.suggestFlags,
+		output:                flagSet.output,
+		commands:              append([]*command(nil), flagSet.commands...),
+		parentName:            flagSet.parentName,
+		configEnvExpansion:    flagSet.configEnvExpansion,
+		configEnvStrict:       flagSet.configEnvStrict,
+		allowAbbreviations:    flagSet.allowAbbreviations,
+		configPreprocessor:    flagSet.configPreprocessor,
+		deferFlagCollisions:   flagSet.deferFlagCollisions,
+		flagCollisions:        append([]error(nil), flagSet.flagCollisions...),
+	}
+
+	// copy flag data once per flag so that short and long names keep sharing it
+	clonedData := make(map[*FlagData]*FlagData)
+	cloneData := func(data *FlagData) *FlagData {
+		if cloned, ok := clonedData[data]; ok {
+			return cloned
+		}
+		cloned := *data
+		cloned.flagSet = clone
+		clonedData[data] = &cloned
+		return &cloned
+	}
+	flagSet.flagKeys.forEach(func(key string, data *FlagData) {
+		clone.flagKeys.Set(key, cloneData(data))
+	})
+	flagSet.configOnlyKeys.forEach(func(key string, data *FlagData) {
+		clone.configOnlyKeys.Set(key, cloneData(data))
+	})
+	return clone
+}
+
+// setFlagKey registers a flag name, validating it first if enabled
+func (flagSet *FlagSet) setFlagKey(name string, flagData *FlagData) {
+	// non-empty names are validated by checkDuplicateFlag before registration
+	if flagSet.validateFlagNames && name == "" {
+		if err := validateFlagName(name, flagData); err != nil {
+			panic(err)
+		}
+	}
+	flagData.flagSet = flagSet
+	flagSet.flagKeys.Set(name, flagData)
+}
+
+// checkDuplicateFlag panics if the short or long name of a new flag is
+// already used by a registered flag, as a short or as a long name, or is
+// invalid when enabled with SetValidateFlagNames. It is called before
+// registering the flag on the command line, which panics on some invalid names.
+//
+// With SetDeferFlagCollisions the duplicate is recorded for Validate instead
+// and false is returned, the flag must then not be registered.
+func (flagSet *FlagSet) checkDuplicateFlag(long, short string) bool {
+	if short != "" && short == long {
+		return flagSet.flagCollision(fmt.Errorf("flag %s uses %q as both short and long name", flagNames(long, short), short))
+	}
+	for _, name := range []string{short, long} {
+		if name == "" {
+			continue
+		}
+		if flagSet.validateFlagNames {
+			if err := validateFlagName(name, &FlagData{short: short, long: long}); err != nil {
+				panic(err)
+			}
+		}
+		if existing, ok := flagSet.flagKeys.values[name]; ok {
+			return flagSet.flagCollision(fmt.Errorf("flag name %q of %s is already registered by %s", name, flagNames(long, short), flagNames(existing.long, existing.short)))
+		}
+	}
+	return true
+}
+
+// flagCollision panics with the error of a duplicate flag name, or records
+// it for Validate when enabled with SetDeferFlagCollisions. It returns false.
+func (flagSet *FlagSet) flagCollision(err error) bool {
+	if !flagSet.deferFlagCollisions {
+		panic(err)
+	}
+	flagSet.flagCollisions = append(flagSet.flagCollisions, err)
+	return false
+}
+
+// flagNames returns the names of a flag as shown in errors (ex: -o, -output)
+func flagNames(long, short string) string {
+	var names []string
+	if short != "" {
+		names = append(names, "-"+short)
+	}
+	if long != "" {
+		names = append(names, "-"+long)
 	}
+	return strings.Join(names, ", ")
+}
+
+// Validate checks the registered flags before parsing, returning a combined
+// error listing every invalid name, including aliases. Duplicate names and
+// aliases colliding with the name of another flag panic at registration
+// unless SetDeferFlagCollisions is enabled, they are then listed too.
+func (flagSet *FlagSet) Validate() error {
+	errs := append([]error(nil), flagSet.flagCollisions...)
+	flagSet.flagKeys.forEach(func(key string, data *FlagData) {
+		if err := validateFlagName(key, data); err != nil {
+			errs = append(errs, err)
+		}
+	})
+	return errors.Join(errs...)
+}
+
+// validateFlagName checks a short or long name of a flag. An empty
+// name is valid only if the flag has another non-empty name.
+func validateFlagName(name string, flagData *FlagData) error {
+	if name == "" {
+		if flagData.short == "" && flagData.long == "" {
+			return fmt.Errorf("invalid flag with usage %q: short and long names cannot both be empty", flagData.usage)
+		}
+		return nil
+	}
+	if strings.TrimSpace(name) != name {
+		return fmt.Errorf("invalid flag name %q: name cannot have leading or trailing whitespace", name)
+	}
+	if strings.HasPrefix(name, "-") {
+		return fmt.Errorf("invalid flag name %q: name cannot start with '-'", name)
+	}
+	for _, char := range name {
+		if !isValidFlagNameChar(char) {
+			return fmt.Errorf("invalid flag name %q: character %q is not allowed, use letters, digits, '-', '_' or '.'", name, char)
+		}
+	}
+	return nil
+}
+
+func isValidFlagNameChar(char rune) bool {
+	return char >= 'a' && char <= 'z' || char >= 'A' && char <= 'Z' || char >= '0' && char <= '9' ||
+		char == '-' || char == '_' || char == '.'
 }
 
 func newInsertionOrderedMap() InsertionOrderedMap {
@@ -96,17 +439,631 @@ func (flagSet *FlagSet) SetGroup(name, description string) {
 	flagSet.groups = append(flagSet.groups, groupData{name: name, description: description})
 }
 
+// metaGroup is the group of callback flags set with SetMetaGroup
+var metaGroup = groupData{name: "meta", description: "Meta"}
+
+// SetMetaGroup moves the callback flags without a group (ex: -update, -version)
+// to a meta group, rendered after all other groups (including other options)
+// in usage. Callback flags registered later are added to it unless grouped.
+func (flagSet *FlagSet) SetMetaGroup() {
+	flagSet.metaGroup = true
+	flagSet.flagKeys.forEach(func(key string, data *FlagData) {
+		if _, ok := data.field.(*callBackVar); ok && data.group == "" {
+			data.group = metaGroup.name
+		}
+	})
+}
+
+// SetAllowExperimental enables or disables the usage of experimental flags
+func (flagSet *FlagSet) SetAllowExperimental(allow bool) {
+	flagSet.allowExperimental = allow
+}
+
+// SetEqualLength requires two slice flags to have the same number of values
+// after parsing (ex: paired -name and -value lists).
+func (flagSet *FlagSet) SetEqualLength(flagA, flagB string) {
+	flagSet.equalLengthFlags = append(flagSet.equalLengthFlags, [2]string{flagA, flagB})
+}
+
+// SetConfigStrict enables or disables returning an error when a config
+// file contains keys not matching any flag, config-only flag or config key.
+func (flagSet *FlagSet) SetConfigStrict(strict bool) {
+	flagSet.configStrict = strict
+}
+
+// SetConfigEnvExpansion enables expanding environment variables
+// (ex: token: ${API_TOKEN}) in the string values of merged config files.
+// Undefined variables expand to empty unless SetConfigEnvStrict is enabled.
+func (flagSet *FlagSet) SetConfigEnvExpansion(expand bool) {
+	flagSet.configEnvExpansion = expand
+}
+
+// SetConfigEnvStrict enables returning an error when an expanded
+// config value references an undefined environment variable.
+func (flagSet *FlagSet) SetConfigEnvStrict(strict bool) {
+	flagSet.configEnvStrict = strict
+}
+
+// DisableConfigLoading disables reading and creating the default config
+// file during Parse (ex: for ephemeral or CI runs).
+func (flagSet *FlagSet) DisableConfigLoading(disable bool) {
+	flagSet.disableConfigLoading = disable
+}
+
+// OnParsed adds a callback run at the end of Parse once flags are parsed,
+// merged with the config and validated. Callbacks run in the order they
+// were added and the first error is returned by Parse.
+func (flagSet *FlagSet) OnParsed(fn func() error) {
+	flagSet.onParsed = append(flagSet.onParsed, fn)
+}
+
+// AddComputedDefault adds a callback run during Parse once the command line
+// and config values are merged but before validation (ex: deriving the value
+// of a flag from others). Values set with FlagSet.Set count as provided for
+// required checks. Callbacks run in the order they were added and the first
+// error is returned by Parse.
+func (flagSet *FlagSet) AddComputedDefault(fn func(*FlagSet) error) {
+	flagSet.computedDefaults = append(flagSet.computedDefaults, fn)
+}
+
+// SetValidateFlagNames enables validating flag names when flags are
+// registered, panicking with an error naming the flag and the violated
+// rule. By default invalid names only panic when usage is displayed.
+func (flagSet *FlagSet) SetValidateFlagNames(validate bool) {
+	flagSet.validateFlagNames = validate
+}
+
+// SetDeferFlagCollisions makes registering a flag or alias with a name
+// already in use skip it instead of panicking. The collisions are then
+// reported together by Validate (ex: to check the flags in tests).
+func (flagSet *FlagSet) SetDeferFlagCollisions(deferCollisions bool) {
+	flagSet.deferFlagCollisions = deferCollisions
+}
+
+// SetSuggestFlags makes Parse return a ParseError with the CategoryUnknown
+// category for unknown command line flags, suggesting the closest registered
+// flag (ex: did you mean -output?), instead of printing usage and exiting.
+func (flagSet *FlagSet) SetSuggestFlags(suggest bool) {
+	flagSet.suggestFlags = suggest
+}
+
+// SetAllowAbbreviations makes Parse accept unambiguous prefixes of long
+// flag names (ex: -outp for -output). A prefix matching several long
+// names returns a ParseError with the CategoryUnknown category listing them.
+func (flagSet *FlagSet) SetAllowAbbreviations(allow bool) {
+	flagSet.allowAbbreviations = allow
+}
+
+// SetOutput sets the writer for all the output of goflags (usage, warnings,
+// version and the errors of the flag package). By default usage is written
+// to stdout on Parse.
+func (flagSet *FlagSet) SetOutput(w io.Writer) {
+	flagSet.output = w
+	flagSet.CommandLine.SetOutput(w)
+}
+
+// outputWriter returns the writer set with SetOutput or the output
+// of the underlying flag set
+func (flagSet *FlagSet) outputWriter() io.Writer {
+	if flagSet.output != nil {
+		return flagSet.output
+	}
+	return flagSet.CommandLine.Output()
+}
+
+// SetTraceWriter enables writing parse events (flags set, resolved sources and
+// validation results) to the writer as JSON lines.
+func (flagSet *FlagSet) SetTraceWriter(w io.Writer) {
+	flagSet.traceWriter = w
+}
+
+// SetConfigPreprocessor sets a function called with the decoded items of
+// each merged config (including the default config file and includes)
+// before they are applied to the flags. It can rewrite the items in place
+// (ex: rename legacy keys) or reject the config by returning an error.
+func (flagSet *FlagSet) SetConfigPreprocessor(preprocessor func(map[string]interface{}) error) {
+	flagSet.configPreprocessor = preprocessor
+}
+
 // MergeConfigFile reads a config file to merge values from.
 func (flagSet *FlagSet) MergeConfigFile(file string) error {
 	return flagSet.readConfigFile(file)
 }
 
+// MergeConfigFileIfExists reads a config file to merge values from like
+// MergeConfigFile, doing nothing if the file does not exist (ex: optional overlays).
+func (flagSet *FlagSet) MergeConfigFileIfExists(file string) error {
+	if _, err := os.Stat(file); os.IsNotExist(err) {
+		return nil
+	}
+	return flagSet.readConfigFile(file)
+}
+
+// MergeConfigReader reads a config in the given format (yaml, json or toml)
+// to merge values from, like MergeConfigFile. Relative includes are resolved
+// from the current directory.
+func (flagSet *FlagSet) MergeConfigReader(r io.Reader, format string) error {
+	data, err := decodeConfig(r, format)
+	if err != nil {
+		return err
+	}
+	source := format + " config"
+	data, err = flagSet.mergeConfigIncludes(data, source, ".", nil)
+	if err != nil {
+		return err
+	}
+	return flagSet.applyConfigData(data, source)
+}
+
+// defaultConfigProfile is the config section inherited by all profiles
+const defaultConfigProfile = "default"
+
+// MergeConfigProfile reads a config file with top-level profile sections
+// (ex: dev, staging, prod) and merges the values of the given profile only.
+// Keys of the optional "default" section are inherited by the profile,
+// whose own keys take precedence.
+func (flagSet *FlagSet) MergeConfigProfile(file, profile string) error {
+	data, err := flagSet.readConfigData(file, nil)
+	if err != nil {
+		return err
+	}
+	merged := make(map[string]interface{})
+	for _, name := range []string{defaultConfigProfile, profile} {
+		item, ok := data[name]
+		if !ok {
+			if name == profile {
+				return fmt.Errorf("profile %q not found in %s", profile, file)
+			}
+			continue
+		}
+		section, ok := item.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("invalid profile %q in %s: must be a section of config items", name, file)
+		}
+		for key, value := range section {
+			merged[key] = value
+		}
+	}
+	return flagSet.applyConfigData(merged, file+" ("+profile+" profile)")
+}
+
+// decodeConfig decodes config items in the given format (yaml, json or toml)
+func decodeConfig(r io.Reader, format string) (map[string]interface{}, error) {
+	data := make(map[string]interface{})
+	switch strings.ToLower(format) {
+	case "yaml", "yml", "json":
+		// json is decoded as yaml, of which it is a subset
+		if err := yaml.NewDecoder(r).Decode(&data); err != nil {
+			return nil, err
+		}
+	case "toml":
+		if _, err := toml.NewDecoder(r).Decode(&data); err != nil {
+			return nil, err
+		}
+		for key, value := range data {
+			data[key] = normalizeTOMLValue(value)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported config format %q", format)
+	}
+	return data, nil
+}
+
+// normalizeTOMLValue converts the int64 integers of toml to int like yaml
+func normalizeTOMLValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case int64:
+		return int(v)
+	case []interface{}:
+		for i := range v {
+			v[i] = normalizeTOMLValue(v[i])
+		}
+	}
+	return value
+}
+
+// ConfigExtras returns the items of the last merged config whose keys do not
+// match any flag, config-only flag or config key (ex: plugin sections).
+func (flagSet *FlagSet) ConfigExtras() map[string]interface{} {
+	extras := make(map[string]interface{}, len(flagSet.configExtras))
+	for key, value := range flagSet.configExtras {
+		extras[key] = value
+	}
+	return extras
+}
+
+// Set sets the value of the named flag (short or long name) as if it
+// was provided on the command line. The value is validated like on parse
+// (bounds, slice MaxItems and ElementValidator, enum values), returning a
+// ParseError with the CategoryInvalid category if it is invalid. The
+// previous value is then kept.
+func (flagSet *FlagSet) Set(name, value string) error {
+	flagData, ok := flagSet.flagKeys.values[name]
+	if !ok || flagSet.CommandLine.Lookup(name) == nil {
+		return fmt.Errorf("no such flag -%v", name)
+	}
+	if flagSet.isHidden(flagData) {
+		return errExperimentalFlag(flagData)
+	}
+	key := flagData.canonicalName()
+	if err := flagSet.checkSetValue(key, flagData, value); err != nil {
+		return err
+	}
+	if err := flagSet.CommandLine.Set(key, value); err != nil {
+		return newParseError(CategoryInvalid, key, value, "invalid value %q for flag -%v: %v", value, key, err)
+	}
+	return nil
+}
+
+// checkSetValue validates a value for a flag with bounds or slice
+// validation before it is set, so a rejected value is never marked as
+// provided on the command line. The previous value is restored after.
+func (flagSet *FlagSet) checkSetValue(key string, flagData *FlagData, value string) error {
+	currentFlag := flagSet.CommandLine.Lookup(key)
+	_, isSlice := currentFlag.Value.(*StringSlice)
+	hasBounds := flagData.minInt != nil || flagData.maxInt != nil || flagData.minDuration != nil || flagData.maxDuration != nil
+	if !isSlice && !hasBounds {
+		return nil
+	}
+	restore := snapshotFlagValue(currentFlag.Value)
+	defer restore()
+	if err := currentFlag.Value.Set(value); err != nil {
+		return newParseError(CategoryInvalid, key, value, "invalid value %q for flag -%v: %v", value, key, err)
+	}
+	if err := flagSet.validateFlagBounds(key, flagData); err != nil {
+		return err
+	}
+	return flagSet.validateFlagSliceValues(key)
+}
+
+// snapshotFlagValue returns a function restoring the current value of a
+// flag with bounds or slice validation
+func snapshotFlagValue(value flag.Value) func() {
+	if stringSlice, ok := value.(*StringSlice); ok {
+		previous := append(StringSlice(nil), *stringSlice...)
+		return func() { *stringSlice = previous }
+	}
+	previous := value.String()
+	return func() { _ = value.Set(previous) }
+}
+
+// Args returns the non-flag arguments remaining after parsing.
+//
+// Flag parsing stops at the first non-flag argument or at the "--"
+// terminator; the arguments after "--" are returned verbatim, even
+// if they look like flags.
+func (flagSet *FlagSet) Args() []string {
+	return flagSet.CommandLine.Args()
+}
+
+// NArg returns the number of non-flag arguments remaining after parsing.
+func (flagSet *FlagSet) NArg() int {
+	return flagSet.CommandLine.NArg()
+}
+
+// ProvidedFlags returns the names of the flags explicitly provided on the
+// command line or by a merged config file, in registration order.
+//
+// Short and long names of a flag are reported once using the long name.
+func (flagSet *FlagSet) ProvidedFlags() []string {
+	provided := flagSet.providedFlags()
+
+	var names []string
+	flagSet.flagKeys.forEach(func(key string, data *FlagData) {
+		if key != data.canonicalName() {
+			return
+		}
+		if _, ok := provided[data]; ok {
+			names = append(names, key)
+		}
+	})
+	return names
+}
+
+// Changed returns true if the named flag (short, long or alias name) was
+// explicitly set on the command line or by a merged config file.
+func (flagSet *FlagSet) Changed(name string) bool {
+	flagData, ok := flagSet.flagKeys.values[name]
+	if !ok {
+		return false
+	}
+	_, ok = flagSet.providedFlags()[flagData]
+	return ok
+}
+
+// providedFlags returns the flags set on the command line or by a config file
+func (flagSet *FlagSet) providedFlags() map[*FlagData]struct{} {
+	provided := flagSet.commandLineFlags()
+	for data := range flagSet.configFlags {
+		provided[data] = struct{}{}
+	}
+	return provided
+}
+
+// commandLineFlags returns the flags set on the command line
+func (flagSet *FlagSet) commandLineFlags() map[*FlagData]struct{} {
+	provided := make(map[*FlagData]struct{})
+	flagSet.CommandLine.Visit(func(fl *flag.Flag) {
+		if data, ok := flagSet.flagKeys.values[fl.Name]; ok {
+			provided[data] = struct{}{}
+		}
+	})
+	return provided
+}
+
+// PrintResolution writes a table of the resolved value of each flag along
+// with its source (cli, config or default) to the writer.
+func (flagSet *FlagSet) PrintResolution(w io.Writer) error {
+	writer := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(writer, "FLAG\tVALUE\tSOURCE")
+	flagSet.forEachResolvedFlag(func(name, value, source string) {
+		fmt.Fprintf(writer, "-%s\t%s\t%s\n", name, value, source)
+	})
+	return writer.Flush()
+}
+
+// MarshalChangedConfig writes the flags explicitly set or whose value differs
+// from their default as a yaml config, in registration order. Secret, hidden
+// and callback flags are never written.
+func (flagSet *FlagSet) MarshalChangedConfig(w io.Writer) error {
+	provided := flagSet.providedFlags()
+	config := &yaml.Node{Kind: yaml.MappingNode}
+	var err error
+	flagSet.flagKeys.forEach(func(key string, data *FlagData) {
+		if err != nil || key != data.canonicalName() || data.secret || data.skipMarshal || flagSet.isHidden(data) {
+			return
+		}
+		currentFlag := flagSet.CommandLine.Lookup(key)
+		if currentFlag == nil {
+			return
+		}
+		if _, ok := provided[data]; !ok && currentFlag.Value.String() == currentFlag.DefValue {
+			return
+		}
+		valueNode := &yaml.Node{}
+		if err = valueNode.Encode(configValue(currentFlag)); err != nil {
+			return
+		}
+		config.Content = append(config.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, valueNode)
+	})
+	if err != nil {
+		return err
+	}
+	if len(config.Content) == 0 {
+		return nil
+	}
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(config); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// configValue returns the value of a flag as written to config files
+func configValue(currentFlag *flag.Flag) interface{} {
+	switch value := flagValue(currentFlag).(type) {
+	case bool, int, float64:
+		return value
+	}
+	switch value := currentFlag.Value.(type) {
+	case *StringSlice:
+		return []string(*value)
+	case *EnumSliceVar:
+		return *value.value
+	}
+	return currentFlag.Value.String()
+}
+
+// forEachResolvedFlag calls fn with the resolved value and source of each flag
+func (flagSet *FlagSet) forEachResolvedFlag(fn func(name, value, source string)) {
+	cliFlags := flagSet.commandLineFlags()
+
+	flagSet.flagKeys.forEach(func(key string, data *FlagData) {
+		if key != data.canonicalName() || flagSet.isHidden(data) {
+			return
+		}
+		var value string
+		if currentFlag := flagSet.CommandLine.Lookup(key); currentFlag != nil {
+			value = currentFlag.Value.String()
+		} else if data.field != nil {
+			value = data.field.String()
+		}
+
+		source := "default"
+		if _, ok := cliFlags[data]; ok {
+			source = "cli"
+		} else if _, ok := flagSet.configFlags[data]; ok {
+			source = "config"
+		}
+		if data.secret && value != "" {
+			value = secretMask
+		}
+		fn(key, value, source)
+	})
+}
+
 // Parse parses the flags provided to the library.
 func (flagSet *FlagSet) Parse() error {
-	flagSet.CommandLine.SetOutput(os.Stdout)
+	return flagSet.ParseArgs(os.Args[1:])
+}
+
+// ParseArgs parses the given arguments (without program name) instead of
+// os.Args, running the same config merge and validation as Parse.
+func (flagSet *FlagSet) ParseArgs(args []string) error {
+	if flagSet.output != nil {
+		flagSet.CommandLine.SetOutput(flagSet.output)
+	} else {
+		flagSet.CommandLine.SetOutput(os.Stdout)
+	}
 	flagSet.CommandLine.Usage = flagSet.usageFunc
-	_ = flagSet.CommandLine.Parse(os.Args[1:])
+	flagSet.helpRequested = false
+	if flagSet.disableBuiltinHelp || flagSet.disableExitOnHelp {
+		strippedArgs, helpRequested := flagSet.stripHelpArgs(args)
+		flagSet.helpRequested = helpRequested
+		if flagSet.disableBuiltinHelp {
+			args = strippedArgs
+		} else if helpRequested {
+			// print usage and return instead of exiting from the flag package
+			flagSet.args = args
+			flagSet.usageFunc()
+			flagSet.args = nil
+			return nil
+		}
+	}
+	if flagSet.allowAbbreviations {
+		expandedArgs, err := flagSet.expandAbbreviations(args)
+		if err != nil {
+			return err
+		}
+		args = expandedArgs
+	}
+	if flagSet.suggestFlags {
+		if err := flagSet.checkUnknownFlags(args); err != nil {
+			return err
+		}
+	}
+	flagSet.args = args
+	_ = flagSet.CommandLine.Parse(args)
+	flagSet.args = nil
+	flagSet.CommandLine.Visit(func(fl *flag.Flag) {
+		flagSet.trace(traceEvent{Event: traceFlagSet, Flag: fl.Name, Value: fl.Value.String(), Source: "cli"})
+	})
+
+	if !flagSet.disableConfigLoading {
+		if err := flagSet.loadDefaultConfig(); err != nil {
+			return err
+		}
+	}
+	if err := flagSet.readFlagFiles(); err != nil {
+		return err
+	}
+	for _, fn := range flagSet.computedDefaults {
+		if err := fn(flagSet); err != nil {
+			return err
+		}
+	}
+	flagSet.forEachResolvedFlag(func(name, value, source string) {
+		flagSet.trace(traceEvent{Event: traceSourceResolved, Flag: name, Value: value, Source: source})
+	})
 
+	err := flagSet.validate()
+	flagSet.traceValidation(err)
+	if err != nil {
+		var parseErr *ParseError
+		if errors.As(err, &parseErr) && parseErr.Category == CategoryRequired && flagSet.CommandLine.ErrorHandling() == flag.ExitOnError {
+			_ = flagSet.PrintMissingRequired(flagSet.outputWriter())
+		}
+		return err
+	}
+	for _, fn := range flagSet.onParsed {
+		if err := fn(); err != nil {
+			return err
+		}
+	}
+	return flagSet.parseCommand()
+}
+
+// registerAlias registers an alias of a flag on the command line,
+// sharing the value of the flag
+func (flagSet *FlagSet) registerAlias(data *FlagData, alias string) {
+	existing, ok := flagSet.flagKeys.values[alias]
+	if existing == data {
+		return
+	}
+	if ok {
+		flagSet.flagCollision(fmt.Errorf("alias %q of %s is already registered by %s", alias, flagNames(data.long, data.short), flagNames(existing.long, existing.short)))
+		return
+	}
+	if flagSet.validateFlagNames {
+		if err := validateFlagName(alias, data); err != nil {
+			panic(err)
+		}
+	}
+	currentFlag := flagSet.CommandLine.Lookup(data.canonicalName())
+	if currentFlag == nil {
+		return
+	}
+	flagSet.CommandLine.Var(currentFlag.Value, alias, currentFlag.Usage)
+	flagSet.setFlagKey(alias, data)
+}
+
+// readFlagFiles replaces the values of FromFile flags, once set from
+// all sources, with the trimmed contents of the file they point to
+func (flagSet *FlagSet) readFlagFiles() error {
+	var err error
+	flagSet.flagKeys.forEach(func(key string, data *FlagData) {
+		if err != nil || !data.fromFile || key != data.canonicalName() {
+			return
+		}
+		currentFlag := flagSet.CommandLine.Lookup(key)
+		if currentFlag == nil || currentFlag.Value.String() == "" {
+			return
+		}
+		filePath := currentFlag.Value.String()
+		content, readErr := os.ReadFile(filePath)
+		if readErr != nil {
+			err = newParseError(CategoryInvalid, key, filePath, "could not read value of flag -%v from %s: %v", key, filePath, readErr)
+			return
+		}
+		if setErr := currentFlag.Value.Set(strings.TrimSpace(string(content))); setErr != nil {
+			err = newParseError(CategoryInvalid, key, filePath, "invalid value of flag -%v in %s: %v", key, filePath, setErr)
+		}
+	})
+	return err
+}
+
+// DisableBuiltinHelp disables the handling of -h and -help so that a caller
+// can render its own usage. They are removed from the parsed arguments
+// (unless registered as flags) and reported by HelpRequested.
+func (flagSet *FlagSet) DisableBuiltinHelp() {
+	flagSet.disableBuiltinHelp = true
+}
+
+// SetExitOnHelp sets whether parse exits the process after printing usage
+// for -h or -help (default true). If false, parse prints usage and returns nil
+// without validating flags, HelpRequested can be used to stop the caller.
+func (flagSet *FlagSet) SetExitOnHelp(exit bool) {
+	flagSet.disableExitOnHelp = !exit
+}
+
+// HelpRequested returns true if -h or -help was given to the last parse
+// with the builtin help disabled or not exiting.
+func (flagSet *FlagSet) HelpRequested() bool {
+	return flagSet.helpRequested
+}
+
+// stripHelpArgs removes the help arguments not registered as flags
+// before the "--" terminator and returns if any was found
+func (flagSet *FlagSet) stripHelpArgs(args []string) ([]string, bool) {
+	var helpRequested bool
+	stripped := make([]string, 0, len(args))
+	for i, arg := range args {
+		// help arguments after a subcommand are handled by the subcommand
+		if arg == "--" || flagSet.lookupCommand(arg) != nil {
+			stripped = append(stripped, args[i:]...)
+			break
+		}
+		if isHelpArg(arg) && flagSet.CommandLine.Lookup(strings.TrimLeft(arg, "-")) == nil {
+			helpRequested = true
+			continue
+		}
+		stripped = append(stripped, arg)
+	}
+	return stripped, helpRequested
+}
+
+// isHelpArg returns true for the -h and -help arguments (with one or two dashes)
+func isHelpArg(arg string) bool {
+	switch arg {
+	case "-h", "--h", "-help", "--help":
+		return true
+	}
+	return false
+}
+
+// loadDefaultConfig merges the default config file, creating it on first run
+func (flagSet *FlagSet) loadDefaultConfig() error {
 	configFilePath, err := flagSet.GetConfigFilePath()
 	if err != nil {
 		return err
@@ -120,6 +1077,107 @@ func (flagSet *FlagSet) Parse() error {
 	return nil
 }
 
+// validate validates the flags after parsing and merging config files
+func (flagSet *FlagSet) validate() error {
+	providedFlags := flagSet.providedFlags()
+	for flagData := range providedFlags {
+		if flagSet.isHidden(flagData) {
+			return errExperimentalFlag(flagData)
+		}
+	}
+	if err := flagSet.validateRequired(providedFlags); err != nil {
+		return err
+	}
+	for _, pair := range flagSet.equalLengthFlags {
+		var lengths [2]int
+		for i, name := range pair {
+			currentFlag := flagSet.CommandLine.Lookup(name)
+			if currentFlag == nil {
+				return fmt.Errorf("no such flag -%v", name)
+			}
+			length, ok := sliceLength(currentFlag.Value)
+			if !ok {
+				return fmt.Errorf("flag -%v is not a slice flag", name)
+			}
+			lengths[i] = length
+		}
+		if lengths[0] != lengths[1] {
+			return newParseError(CategoryInvalid, pair[1], "", "flags -%v and -%v must have the same number of values: got %d and %d", pair[0], pair[1], lengths[0], lengths[1])
+		}
+	}
+	if err := flagSet.validateSliceValues(); err != nil {
+		return err
+	}
+	return flagSet.validateBounds()
+}
+
+// validateRequired checks the required flags are provided in registration order
+func (flagSet *FlagSet) validateRequired(providedFlags map[*FlagData]struct{}) error {
+	missing := flagSet.missingRequiredFlags(providedFlags)
+	if len(missing) == 0 {
+		return nil
+	}
+	key := missing[0].canonicalName()
+	return newParseError(CategoryRequired, key, "", "flag -%v is required", key)
+}
+
+// missingRequiredFlags returns the visible required flags not provided, in registration order
+func (flagSet *FlagSet) missingRequiredFlags(providedFlags map[*FlagData]struct{}) []*FlagData {
+	var missing []*FlagData
+	flagSet.flagKeys.forEach(func(key string, data *FlagData) {
+		if !data.required || key != data.canonicalName() || flagSet.isHidden(data) {
+			return
+		}
+		if _, ok := providedFlags[data]; !ok {
+			missing = append(missing, data)
+		}
+	})
+	return missing
+}
+
+// PrintMissingRequired writes the required flags not provided on the command
+// line or config with their usage to the writer, nothing if none are missing.
+// In the default exit on error mode, it is written to the output by Parse
+// along with the required ParseError.
+func (flagSet *FlagSet) PrintMissingRequired(w io.Writer) error {
+	missing := flagSet.missingRequiredFlags(flagSet.providedFlags())
+	if len(missing) == 0 {
+		return nil
+	}
+	fmt.Fprintf(w, "missing required flags:\n")
+	writer := tabwriter.NewWriter(w, 0, 0, 1, ' ', 0)
+	for _, data := range missing {
+		if currentFlag := flagSet.CommandLine.Lookup(data.canonicalName()); currentFlag != nil {
+			fmt.Fprint(writer, createUsageString(data, currentFlag), "\n")
+		}
+	}
+	return writer.Flush()
+}
+
+// sliceLength returns the number of values of a slice flag
+func sliceLength(value flag.Value) (int, bool) {
+	switch v := value.(type) {
+	case *EnumSliceVar:
+		return len(*v.value), true
+	case *portRangeValue:
+		return len(*v.value), true
+	}
+	reflectValue := reflect.ValueOf(value)
+	if reflectValue.Kind() == reflect.Ptr && reflectValue.Elem().Kind() == reflect.Slice {
+		return reflectValue.Elem().Len(), true
+	}
+	return 0, false
+}
+
+// isHidden returns true if the flag should not be displayed or used
+func (flagSet *FlagSet) isHidden(flagData *FlagData) bool {
+	return flagData.experimental && !flagSet.allowExperimental
+}
+
+func errExperimentalFlag(flagData *FlagData) error {
+	return fmt.Errorf("flag -%v is experimental and experimental mode is not enabled", flagData.canonicalName())
+}
+
 // generateDefaultConfig generates a default YAML config file for a flagset.
 func (flagSet *FlagSet) generateDefaultConfig() []byte {
 	hashes := make(map[string]struct{})
@@ -133,7 +1191,7 @@ func (flagSet *FlagSet) generateDefaultConfig() []byte {
 		flagsToMarshall := make(map[string]interface{})
 
 		flagSet.flagKeys.forEach(func(key string, data *FlagData) {
-			if !data.skipMarshal {
+			if !data.skipMarshal && !data.secret {
 				flagsToMarshall[key] = data.defaultValue
 			}
 		})
@@ -145,32 +1203,99 @@ func (flagSet *FlagSet) generateDefaultConfig() []byte {
 		}
 	}
 
-	flagSet.flagKeys.forEach(func(key string, data *FlagData) {
-		dataHash := data.Hash()
-		if _, ok := hashes[dataHash]; ok {
-			return
+	writeEntries := func(inGroup func(data *FlagData) bool) {
+		flagSet.flagKeys.forEach(func(key string, data *FlagData) {
+			if !inGroup(data) {
+				return
+			}
+			dataHash := data.Hash()
+			if _, ok := hashes[dataHash]; ok {
+				return
+			}
+			hashes[dataHash] = struct{}{}
+			writeConfigEntry(configBuffer, data)
+		})
+	}
+
+	// without groups the entries are written in registration order
+	if len(flagSet.groups) == 0 {
+		writeEntries(func(data *FlagData) bool { return true })
+		return bytes.TrimSuffix(configBuffer.Bytes(), []byte("\n\n"))
+	}
+
+	grouped := make(map[*FlagData]struct{})
+	for _, group := range flagSet.groups {
+		var hasEntries bool
+		flagSet.flagKeys.forEach(func(key string, data *FlagData) {
+			if strings.EqualFold(data.group, group.name) {
+				hasEntries = true
+				grouped[data] = struct{}{}
+			}
+		})
+		if !hasEntries {
+			continue
+		}
+		configBuffer.WriteString("# ")
+		configBuffer.WriteString(normalizeGroupDescription(group.description))
+		configBuffer.WriteString("\n\n")
+		writeEntries(func(data *FlagData) bool { return strings.EqualFold(data.group, group.name) })
+	}
+
+	// flags without a (declared) group are written last
+	var hasOtherOptions bool
+	flagSet.flagKeys.forEach(func(key string, data *FlagData) {
+		if _, ok := grouped[data]; !ok {
+			hasOtherOptions = true
+		}
+	})
+	if hasOtherOptions {
+		configBuffer.WriteString("# ")
+		configBuffer.WriteString(normalizeGroupDescription(flagSet.OtherOptionsGroupName))
+		configBuffer.WriteString("\n\n")
+		writeEntries(func(data *FlagData) bool {
+			_, ok := grouped[data]
+			return !ok
+		})
+	}
+	return bytes.TrimSuffix(configBuffer.Bytes(), []byte("\n\n"))
+}
+
+// writeConfigEntry writes the commented usage and default value of a flag
+func writeConfigEntry(configBuffer *bytes.Buffer, data *FlagData) {
+	if data.configComment != "" {
+		for _, line := range strings.Split(strings.TrimRight(data.configComment, "\n"), "\n") {
+			configBuffer.WriteString(strings.TrimRight("# "+line, " "))
+			configBuffer.WriteString("\n")
 		}
-		hashes[dataHash] = struct{}{}
-
+	} else {
 		configBuffer.WriteString("# ")
 		configBuffer.WriteString(strings.ToLower(data.usage))
 		configBuffer.WriteString("\n")
-		configBuffer.WriteString("#")
-		configBuffer.WriteString(data.long)
-		configBuffer.WriteString(": ")
-		switch dv := data.defaultValue.(type) {
-		case string:
-			configBuffer.WriteString(dv)
-		case flag.Value:
-			configBuffer.WriteString(dv.String())
-		case StringSlice:
+	}
+	configBuffer.WriteString("#")
+	configBuffer.WriteString(data.long)
+	configBuffer.WriteString(": ")
+	// secret defaults (ex: read from env) are never written to the config
+	if data.secret {
+		configBuffer.WriteString("\n\n")
+		return
+	}
+	switch dv := data.defaultValue.(type) {
+	case string:
+		configBuffer.WriteString(dv)
+	case flag.Value:
+		configBuffer.WriteString(dv.String())
+	case StringSlice:
+		if data.configCommaForm {
+			configBuffer.WriteString(strings.Join(dv, string(data.configSeparator)))
+		} else {
 			configBuffer.WriteString(dv.String())
 		}
+	case time.Duration:
+		configBuffer.WriteString(dv.String())
+	}
 
-		configBuffer.WriteString("\n\n")
-	})
-
-	return bytes.TrimSuffix(configBuffer.Bytes(), []byte("\n\n"))
+	configBuffer.WriteString("\n\n")
 }
 
 // CreateGroup within the flagset
@@ -181,50 +1306,140 @@ func (flagSet *FlagSet) CreateGroup(groupName, description string, flags ...*Fla
 	}
 }
 
+// Groups returns the groups in the order they were set, with their flags
+// in the same order as the usage output. Flags without a group are returned
+// in a trailing group named after OtherOptionsGroupName, followed by the
+// meta group if enabled with SetMetaGroup.
+func (flagSet *FlagSet) Groups() []FlagGroup {
+	var groups []FlagGroup
+	for _, group := range flagSet.groups {
+		groups = append(groups, FlagGroup{Name: group.name, Description: group.description})
+	}
+	otherOptions := FlagGroup{Name: flagSet.OtherOptionsGroupName, Description: flagSet.OtherOptionsGroupName}
+	meta := FlagGroup{Name: metaGroup.name, Description: metaGroup.description}
+
+	seen := make(map[*FlagData]struct{})
+	flagSet.forEachUsageFlag(func(key string, data *FlagData) {
+		if flagSet.CommandLine.Lookup(key) == nil || flagSet.isHidden(data) {
+			return
+		}
+		if _, ok := seen[data]; ok {
+			return
+		}
+		seen[data] = struct{}{}
+
+		if data.group == "" {
+			otherOptions.Flags = append(otherOptions.Flags, data.canonicalName())
+			return
+		}
+		if flagSet.metaGroup && data.group == metaGroup.name {
+			meta.Flags = append(meta.Flags, data.canonicalName())
+			return
+		}
+		for i := range groups {
+			if strings.EqualFold(groups[i].Name, data.group) {
+				groups[i].Flags = append(groups[i].Flags, data.canonicalName())
+				return
+			}
+		}
+	})
+	if len(otherOptions.Flags) > 0 {
+		groups = append(groups, otherOptions)
+	}
+	if len(meta.Flags) > 0 {
+		groups = append(groups, meta)
+	}
+	return groups
+}
+
+// GroupOf returns the group name of a flag by its short or long name.
+// The bool is false if the flag does not exist or has no group.
+func (flagSet *FlagSet) GroupOf(name string) (string, bool) {
+	data, ok := flagSet.flagKeys.values[name]
+	if !ok || data.group == "" {
+		return "", false
+	}
+	return data.group, true
+}
+
 // readConfigFile reads the config file and returns any flags
 // that might have been set by the config file.
 //
 // Command line flags however always take precedence over config file ones.
 func (flagSet *FlagSet) readConfigFile(filePath string) error {
-	file, err := os.Open(filePath)
+	data, err := flagSet.readConfigData(filePath, nil)
 	if err != nil {
 		return err
 	}
-	defer file.Close()
+	return flagSet.applyConfigData(data, filePath)
+}
 
-	data := make(map[string]interface{})
-	err = yaml.NewDecoder(file).Decode(&data)
-	if err != nil {
+// preprocessConfig calls the config preprocessor, if any, with the decoded
+// config items of a source (ex: file path)
+func (flagSet *FlagSet) preprocessConfig(data map[string]interface{}, source string) error {
+	if flagSet.configPreprocessor == nil {
+		return nil
+	}
+	if err := flagSet.configPreprocessor(data); err != nil {
+		return fmt.Errorf("invalid config %s: %w", source, err)
+	}
+	return nil
+}
+
+// applyConfigData merges the decoded config items of a source (ex: file path)
+// into the flags not set on the command line
+func (flagSet *FlagSet) applyConfigData(data map[string]interface{}, source string) error {
+	if err := flagSet.preprocessConfig(data, source); err != nil {
 		return err
 	}
+	if flagSet.configEnvExpansion {
+		undefined := make(map[string]struct{})
+		for key, value := range data {
+			data[key] = expandConfigEnv(value, undefined)
+		}
+		if flagSet.configEnvStrict && len(undefined) > 0 {
+			names := maps.Keys(undefined)
+			sort.Strings(names)
+			return fmt.Errorf("undefined environment variables in %s: %s", source, strings.Join(names, ", "))
+		}
+	}
+	unknownKeys := flagSet.unknownConfigKeys(data)
+	if flagSet.configStrict && len(unknownKeys) > 0 {
+		return fmt.Errorf("unknown config keys in %s: %s", source, strings.Join(unknownKeys, ", "))
+	}
+	flagSet.configExtras = make(map[string]interface{}, len(unknownKeys))
+	for _, key := range unknownKeys {
+		flagSet.configExtras[key] = data[key]
+	}
+	cliFlags := flagSet.commandLineFlags()
 	flagSet.CommandLine.VisitAll(func(fl *flag.Flag) {
-		item, ok := data[fl.Name]
+		item, ok := flagSet.lookupConfigItem(data, fl.Name)
 		value := fl.Value.String()
 
-		if strings.EqualFold(fl.DefValue, value) && ok {
-			switch itemValue := item.(type) {
-			case string:
-				_ = fl.Value.Set(itemValue)
-			case bool:
-				_ = fl.Value.Set(strconv.FormatBool(itemValue))
-			case int:
-				_ = fl.Value.Set(strconv.Itoa(itemValue))
-			case time.Duration:
-				_ = fl.Value.Set(itemValue.String())
-			case []interface{}:
-				for _, v := range itemValue {
-					vStr, ok := v.(string)
-					if ok {
-						_ = fl.Value.Set(vStr)
-					}
+		// flags given on the command line (even with their default value) are kept
+		if flagData, exists := flagSet.flagKeys.values[fl.Name]; exists {
+			if _, isCliFlag := cliFlags[flagData]; isCliFlag {
+				if ok && appendConfigValues(fl.Value, item) {
+					flagSet.configFlags[flagData] = struct{}{}
 				}
+				return
 			}
 		}
+		if strings.EqualFold(fl.DefValue, value) && ok {
+			if flagData, ok := flagSet.flagKeys.values[fl.Name]; ok {
+				flagSet.configFlags[flagData] = struct{}{}
+			}
+			setConfigValue(fl, item)
+			flagSet.trace(traceEvent{Event: traceFlagSet, Flag: fl.Name, Value: fl.Value.String(), Source: "config"})
+		} else if !ok && strings.EqualFold(fl.DefValue, value) {
+			flagSet.applyConfigDefault(data, fl)
+		}
 	})
 
 	flagSet.configOnlyKeys.forEach(func(key string, flagData *FlagData) {
-		item, ok := data[key]
+		item, ok := flagSet.lookupConfigItem(data, key)
 		if ok {
+			flagSet.configFlags[flagData] = struct{}{}
 			fl := flag.Lookup(key)
 			if fl == nil {
 				flag.Var(flagData.field, key, flagData.usage)
@@ -242,15 +1457,318 @@ func (flagSet *FlagSet) readConfigFile(filePath string) error {
 				for _, v := range data {
 					vStr, ok := v.(string)
 					if ok {
-						_ = fl.Value.Set(vStr)
+						_ = setConfigListItem(fl.Value, vStr)
 					}
 				}
 			}
+			flagSet.trace(traceEvent{Event: traceFlagSet, Flag: key, Value: fl.Value.String(), Source: "config"})
 		}
 	})
 	return nil
 }
 
+// setConfigValue sets the value of a flag from a config item
+func setConfigValue(fl *flag.Flag, item interface{}) {
+	switch itemValue := item.(type) {
+	case string:
+		_ = fl.Value.Set(configBoolString(fl, itemValue))
+	case bool:
+		_ = fl.Value.Set(strconv.FormatBool(itemValue))
+	case int:
+		_ = fl.Value.Set(strconv.Itoa(itemValue))
+	case float64:
+		_ = fl.Value.Set(strconv.FormatFloat(itemValue, 'f', -1, 64))
+	case time.Duration:
+		_ = fl.Value.Set(itemValue.String())
+	case time.Time:
+		// unquoted yaml timestamps are decoded as time
+		if value, ok := fl.Value.(*timeValue); ok {
+			*value.value = itemValue
+		} else {
+			_ = fl.Value.Set(itemValue.Format(time.RFC3339))
+		}
+	case []interface{}:
+		for _, v := range itemValue {
+			switch v := v.(type) {
+			case string:
+				_ = setConfigListItem(fl.Value, v)
+			case int:
+				_ = setConfigListItem(fl.Value, strconv.Itoa(v))
+			}
+		}
+	}
+}
+
+// applyConfigDefault sets a flag from its DefaultFromConfig key. The default
+// value of the flag is updated so that the flag key of a later config still
+// overrides it.
+func (flagSet *FlagSet) applyConfigDefault(data map[string]interface{}, fl *flag.Flag) {
+	flagData, ok := flagSet.flagKeys.values[fl.Name]
+	if !ok || flagData.defaultConfigKey == "" || fl.Name != flagData.canonicalName() {
+		return
+	}
+	item, ok := configPathItem(data, flagData.defaultConfigKey)
+	if !ok {
+		return
+	}
+	if flagData.registeredDefValue == nil {
+		registeredDefValue := fl.DefValue
+		flagData.registeredDefValue = &registeredDefValue
+	}
+	setConfigValue(fl, item)
+	for _, name := range []string{flagData.short, flagData.long} {
+		if currentFlag := flagSet.CommandLine.Lookup(name); currentFlag != nil {
+			currentFlag.DefValue = fl.Value.String()
+		}
+	}
+	flagSet.trace(traceEvent{Event: traceFlagSet, Flag: fl.Name, Value: fl.Value.String(), Source: "config default"})
+}
+
+// configPathItem returns the config item of a key, or of a dotted path of
+// nested keys (ex: defaults.timeout for defaults: {timeout: 10})
+func configPathItem(data map[string]interface{}, key string) (interface{}, bool) {
+	if item, ok := data[key]; ok {
+		return item, true
+	}
+	parts := strings.Split(key, ".")
+	current := data
+	for i, part := range parts {
+		item, ok := current[part]
+		if !ok {
+			return nil, false
+		}
+		if i == len(parts)-1 {
+			return item, true
+		}
+		if current, ok = item.(map[string]interface{}); !ok {
+			return nil, false
+		}
+	}
+	return nil, false
+}
+
+// unknownConfigKeys returns the sorted config keys not matching any flag
+func (flagSet *FlagSet) unknownConfigKeys(data map[string]interface{}) []string {
+	knownKeys := make(map[string]struct{})
+	flagSet.flagKeys.forEach(func(key string, flagData *FlagData) {
+		knownKeys[normalizeConfigKey(key)] = struct{}{}
+		if flagData.configKey != "" {
+			knownKeys[normalizeConfigKey(flagData.configKey)] = struct{}{}
+		}
+		if flagData.defaultConfigKey != "" {
+			knownKeys[normalizeConfigKey(flagData.defaultConfigKey)] = struct{}{}
+			knownKeys[normalizeConfigKey(strings.Split(flagData.defaultConfigKey, ".")[0])] = struct{}{}
+		}
+	})
+	var unknownKeys []string
+	for key := range data {
+		if _, ok := knownKeys[normalizeConfigKey(key)]; !ok {
+			unknownKeys = append(unknownKeys, key)
+		}
+	}
+	sort.Strings(unknownKeys)
+	return unknownKeys
+}
+
+// configIncludeKey is the config key listing other config files to include
+const configIncludeKey = "include"
+
+// readConfigData decodes a config file, merging the files from its include
+// directive first (relative to its directory) so its own keys take precedence.
+// includeStack holds the files being read to detect include cycles.
+func (flagSet *FlagSet) readConfigData(filePath string, includeStack []string) (map[string]interface{}, error) {
+	absPath, err := filepath.Abs(filePath)
+	if err != nil {
+		return nil, err
+	}
+	for _, included := range includeStack {
+		if included == absPath {
+			return nil, fmt.Errorf("config include cycle: %s -> %s", strings.Join(includeStack, " -> "), absPath)
+		}
+	}
+	includeStack = append(includeStack, absPath)
+
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	data, err := decodeConfig(file, "yaml")
+	if err != nil {
+		return nil, err
+	}
+	return flagSet.mergeConfigIncludes(data, filePath, filepath.Dir(absPath), includeStack)
+}
+
+// mergeConfigIncludes returns the config items of the include directive of a
+// config source (ex: file path) merged with its own items. Relative includes
+// are resolved from baseDir.
+func (flagSet *FlagSet) mergeConfigIncludes(data map[string]interface{}, source, baseDir string, includeStack []string) (map[string]interface{}, error) {
+	includeItem, ok := data[configIncludeKey]
+	if !ok || flagSet.flagKeys.values[configIncludeKey] != nil {
+		return data, nil
+	}
+	delete(data, configIncludeKey)
+
+	var includes []string
+	switch value := includeItem.(type) {
+	case string:
+		includes = append(includes, value)
+	case []interface{}:
+		for _, v := range value {
+			include, ok := v.(string)
+			if !ok {
+				return nil, fmt.Errorf("invalid include %v in %s: must be a file path", v, source)
+			}
+			includes = append(includes, include)
+		}
+	default:
+		return nil, fmt.Errorf("invalid include in %s: must be a file path or a list of file paths", source)
+	}
+
+	merged := make(map[string]interface{})
+	for _, include := range includes {
+		if !filepath.IsAbs(include) {
+			include = filepath.Join(baseDir, include)
+		}
+		includedData, err := flagSet.readConfigData(include, includeStack)
+		if err != nil {
+			return nil, err
+		}
+		for key, value := range includedData {
+			merged[key] = value
+		}
+	}
+	for key, value := range data {
+		merged[key] = value
+	}
+	return merged, nil
+}
+
+// expandConfigEnv expands the environment variables of the string values
+// of a config item, adding the undefined variables to undefined
+func expandConfigEnv(item interface{}, undefined map[string]struct{}) interface{} {
+	switch value := item.(type) {
+	case string:
+		return os.Expand(value, func(name string) string {
+			envValue, ok := os.LookupEnv(name)
+			if !ok {
+				undefined[name] = struct{}{}
+			}
+			return envValue
+		})
+	case []interface{}:
+		for i := range value {
+			value[i] = expandConfigEnv(value[i], undefined)
+		}
+	case map[string]interface{}:
+		for key := range value {
+			value[key] = expandConfigEnv(value[key], undefined)
+		}
+	}
+	return item
+}
+
+// configBoolString converts yes/no and on/off config values of bool flags
+// to true/false. "true", "false", "1" and "0" are already accepted by Set.
+func configBoolString(fl *flag.Flag, value string) string {
+	if _, ok := flagValue(fl).(bool); !ok {
+		return value
+	}
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "yes", "on":
+		return "true"
+	case "no", "off":
+		return "false"
+	}
+	return value
+}
+
+// lookupConfigItem returns the config item for a flag name, using the
+// config key override of the flag if it is present in the config.
+func (flagSet *FlagSet) lookupConfigItem(data map[string]interface{}, name string) (interface{}, bool) {
+	item, ok := flagSet.configItem(data, name)
+	flagData, exists := flagSet.flagKeys.values[name]
+	// override keys are only resolved once per flag, for its canonical name
+	if !exists || flagData.configKey == "" || flagData.canonicalName() != name {
+		return item, ok
+	}
+	overrideItem, overrideOk := flagSet.configItem(data, flagData.configKey)
+	if !overrideOk {
+		return item, ok
+	}
+	if ok {
+		fmt.Fprintf(flagSet.outputWriter(), "warning: config keys %q and %q are both set for flag -%s, using %q\n", flagData.configKey, name, name, flagData.configKey)
+	}
+	return overrideItem, true
+}
+
+// configItem returns the config item of a key. If the key is not present,
+// a key only differing by "_" and "-" (ex: max_retries for max-retries) is used
+// unless it is the name of another flag.
+func (flagSet *FlagSet) configItem(data map[string]interface{}, key string) (interface{}, bool) {
+	if item, ok := data[key]; ok {
+		return item, true
+	}
+	normalizedKey := normalizeConfigKey(key)
+	var matchedKey string
+	for dataKey := range data {
+		if normalizeConfigKey(dataKey) != normalizedKey || flagSet.flagKeys.values[dataKey] != nil {
+			continue
+		}
+		if matchedKey == "" || dataKey < matchedKey {
+			matchedKey = dataKey
+		}
+	}
+	if matchedKey == "" {
+		return nil, false
+	}
+	return data[matchedKey], true
+}
+
+// normalizeConfigKey converts the snake-case separators of a key to kebab-case
+func normalizeConfigKey(key string) string {
+	return strings.ReplaceAll(key, "_", "-")
+}
+
+// appendConfigValues prepends config values to the command line values of
+// string slices using MergeAppend, returning true if the values were merged.
+func appendConfigValues(value flag.Value, item interface{}) bool {
+	stringSlice, ok := value.(*StringSlice)
+	if !ok || optionMap[stringSlice].MergeMode != MergeAppend {
+		return false
+	}
+	cliValues := *stringSlice
+	*stringSlice = StringSlice{}
+	switch itemValue := item.(type) {
+	case string:
+		_ = stringSlice.Set(itemValue)
+	case []interface{}:
+		for _, v := range itemValue {
+			if vStr, ok := v.(string); ok {
+				_ = stringSlice.setInline(vStr)
+			}
+		}
+	}
+	for _, cliValue := range cliValues {
+		if optionMap[stringSlice].Unique && sliceutil.Contains(*stringSlice, cliValue) {
+			continue
+		}
+		*stringSlice = append(*stringSlice, cliValue)
+	}
+	return true
+}
+
+// setConfigListItem sets an item of a config list value. String slices
+// treat list items as inline values and never read them as files.
+func setConfigListItem(value flag.Value, item string) error {
+	if stringSlice, ok := value.(*StringSlice); ok {
+		return stringSlice.setInline(item)
+	}
+	return value.Set(item)
+}
+
 // VarP adds a Var flag with a shortname and longname
 func (flagSet *FlagSet) VarP(field flag.Value, long, short, usage string) *FlagData {
 	flagData := &FlagData{
@@ -258,13 +1776,16 @@ func (flagSet *FlagSet) VarP(field flag.Value, long, short, usage string) *FlagD
 		long:         long,
 		defaultValue: field,
 	}
+	if !flagSet.checkDuplicateFlag(long, short) {
+		return flagData
+	}
 	if short != "" {
 		flagData.short = short
 		flagSet.CommandLine.Var(field, short, usage)
-		flagSet.flagKeys.Set(short, flagData)
+		flagSet.setFlagKey(short, flagData)
 	}
 	flagSet.CommandLine.Var(field, long, usage)
-	flagSet.flagKeys.Set(long, flagData)
+	flagSet.setFlagKey(long, flagData)
 	return flagData
 }
 
@@ -289,13 +1810,16 @@ func (flagSet *FlagSet) StringVarP(field *string, long, short, defaultValue, usa
 		long:         long,
 		defaultValue: defaultValue,
 	}
+	if !flagSet.checkDuplicateFlag(long, short) {
+		return flagData
+	}
 	if short != "" {
 		flagData.short = short
 		flagSet.CommandLine.StringVar(field, short, defaultValue, usage)
-		flagSet.flagKeys.Set(short, flagData)
+		flagSet.setFlagKey(short, flagData)
 	}
 	flagSet.CommandLine.StringVar(field, long, defaultValue, usage)
-	flagSet.flagKeys.Set(long, flagData)
+	flagSet.setFlagKey(long, flagData)
 	return flagData
 }
 
@@ -311,13 +1835,16 @@ func (flagSet *FlagSet) BoolVarP(field *bool, long, short string, defaultValue b
 		long:         long,
 		defaultValue: strconv.FormatBool(defaultValue),
 	}
+	if !flagSet.checkDuplicateFlag(long, short) {
+		return flagData
+	}
 	if short != "" {
 		flagData.short = short
 		flagSet.CommandLine.BoolVar(field, short, defaultValue, usage)
-		flagSet.flagKeys.Set(short, flagData)
+		flagSet.setFlagKey(short, flagData)
 	}
 	flagSet.CommandLine.BoolVar(field, long, defaultValue, usage)
-	flagSet.flagKeys.Set(long, flagData)
+	flagSet.setFlagKey(long, flagData)
 	return flagData
 }
 
@@ -326,6 +1853,28 @@ func (flagSet *FlagSet) BoolVar(field *bool, long string, defaultValue bool, usa
 	return flagSet.BoolVarP(field, long, "", defaultValue, usage)
 }
 
+// BoolVarEnv adds a bool flag with a shortname and longname with a default value read from env variable
+// with a default value fallback (used as well when the env value is not a valid bool)
+func (flagSet *FlagSet) BoolVarEnv(field *bool, long, short string, defaultValue bool, envName, usage string) *FlagData {
+	if envValue, exists := os.LookupEnv(envName); exists {
+		if value, err := strconv.ParseBool(envValue); err == nil {
+			defaultValue = value
+		}
+	}
+	return flagSet.BoolVarP(field, long, short, defaultValue, usage)
+}
+
+// IntVarEnv adds a int flag with a shortname and longname with a default value read from env variable
+// with a default value fallback (used as well when the env value is not a valid int)
+func (flagSet *FlagSet) IntVarEnv(field *int, long, short string, defaultValue int, envName, usage string) *FlagData {
+	if envValue, exists := os.LookupEnv(envName); exists {
+		if value, err := strconv.Atoi(envValue); err == nil {
+			defaultValue = value
+		}
+	}
+	return flagSet.IntVarP(field, long, short, defaultValue, usage)
+}
+
 // IntVarP adds a int flag with a shortname and longname
 func (flagSet *FlagSet) IntVarP(field *int, long, short string, defaultValue int, usage string) *FlagData {
 	flagData := &FlagData{
@@ -334,13 +1883,16 @@ func (flagSet *FlagSet) IntVarP(field *int, long, short string, defaultValue int
 		long:         long,
 		defaultValue: strconv.Itoa(defaultValue),
 	}
+	if !flagSet.checkDuplicateFlag(long, short) {
+		return flagData
+	}
 	if short != "" {
 		flagData.short = short
 		flagSet.CommandLine.IntVar(field, short, defaultValue, usage)
-		flagSet.flagKeys.Set(short, flagData)
+		flagSet.setFlagKey(short, flagData)
 	}
 	flagSet.CommandLine.IntVar(field, long, defaultValue, usage)
-	flagSet.flagKeys.Set(long, flagData)
+	flagSet.setFlagKey(long, flagData)
 	return flagData
 }
 
@@ -349,6 +1901,20 @@ func (flagSet *FlagSet) IntVar(field *int, long string, defaultValue int, usage
 	return flagSet.IntVarP(field, long, "", defaultValue, usage)
 }
 
+// MinInt sets the minimum allowed value for an int flag,
+// validated during Parse for values from the command line and config.
+func (flagData *FlagData) MinInt(min int) *FlagData {
+	flagData.minInt = &min
+	return flagData
+}
+
+// MaxInt sets the maximum allowed value for an int flag,
+// validated during Parse for values from the command line and config.
+func (flagData *FlagData) MaxInt(max int) *FlagData {
+	flagData.maxInt = &max
+	return flagData
+}
+
 // StringSliceVarP adds a string slice flag with a shortname and longname
 // Use options to customize the behavior
 func (flagSet *FlagSet) StringSliceVarP(field *StringSlice, long, short string, defaultValue StringSlice, usage string, options Options) *FlagData {
@@ -361,17 +1927,22 @@ func (flagSet *FlagSet) StringSliceVarP(field *StringSlice, long, short string,
 	}
 	optionDefaultValues[field] = *field
 	flagData := &FlagData{
-		usage:        usage,
-		long:         long,
-		defaultValue: defaultValue,
+		usage:           usage,
+		long:            long,
+		defaultValue:    defaultValue,
+		configCommaForm: options.ConfigCommaForm,
+		configSeparator: options.separator(),
+	}
+	if !flagSet.checkDuplicateFlag(long, short) {
+		return flagData
 	}
 	if short != "" {
 		flagData.short = short
 		flagSet.CommandLine.Var(field, short, usage)
-		flagSet.flagKeys.Set(short, flagData)
+		flagSet.setFlagKey(short, flagData)
 	}
 	flagSet.CommandLine.Var(field, long, usage)
-	flagSet.flagKeys.Set(long, flagData)
+	flagSet.setFlagKey(long, flagData)
 	return flagData
 }
 
@@ -393,8 +1964,11 @@ func (flagSet *FlagSet) StringSliceVarConfigOnly(field *StringSlice, long string
 		defaultValue: defaultValue,
 		field:        field,
 	}
+	if !flagSet.checkDuplicateFlag(long, "") {
+		return flagData
+	}
 	flagSet.configOnlyKeys.Set(long, flagData)
-	flagSet.flagKeys.Set(long, flagData)
+	flagSet.setFlagKey(long, flagData)
 	return flagData
 }
 
@@ -416,13 +1990,16 @@ func (flagSet *FlagSet) RuntimeMapVarP(field *RuntimeMap, long, short string, de
 		skipMarshal:  true,
 	}
 
+	if !flagSet.checkDuplicateFlag(long, short) {
+		return flagData
+	}
 	if short != "" {
 		flagData.short = short
 		flagSet.CommandLine.Var(field, short, usage)
-		flagSet.flagKeys.Set(short, flagData)
+		flagSet.setFlagKey(short, flagData)
 	}
 	flagSet.CommandLine.Var(field, long, usage)
-	flagSet.flagKeys.Set(long, flagData)
+	flagSet.setFlagKey(long, flagData)
 	return flagData
 }
 
@@ -445,13 +2022,16 @@ func (flagSet *FlagSet) PortVarP(field *Port, long, short string, defaultValue [
 		skipMarshal:  true,
 	}
 
+	if !flagSet.checkDuplicateFlag(long, short) {
+		return flagData
+	}
 	if short != "" {
 		flagData.short = short
 		flagSet.CommandLine.Var(field, short, usage)
-		flagSet.flagKeys.Set(short, flagData)
+		flagSet.setFlagKey(short, flagData)
 	}
 	flagSet.CommandLine.Var(field, long, usage)
-	flagSet.flagKeys.Set(long, flagData)
+	flagSet.setFlagKey(long, flagData)
 	return flagData
 }
 
@@ -462,28 +2042,26 @@ func (flagSet *FlagSet) EnumVar(field *string, long string, defaultValue EnumVar
 
 // EnumVarP adds a enum flag with a shortname and longname
 func (flagSet *FlagSet) EnumVarP(field *string, long, short string, defaultValue EnumVariable, usage string, allowedTypes AllowdTypes) *FlagData {
-	var hasDefaultValue bool
-	for k, v := range allowedTypes {
-		if v == defaultValue {
-			hasDefaultValue = true
-			*field = k
-		}
-	}
-	if !hasDefaultValue {
-		panic("undefined default value")
+	defaultKey, ok := allowedTypes.key(defaultValue)
+	if !ok {
+		panic(fmt.Errorf("invalid default value %d for flag -%v: allowed values are %v", defaultValue, long, allowedTypes.String()))
 	}
+	*field = defaultKey
 	flagData := &FlagData{
 		usage:        usage,
 		long:         long,
 		defaultValue: *field,
 	}
+	if !flagSet.checkDuplicateFlag(long, short) {
+		return flagData
+	}
 	if short != "" {
 		flagData.short = short
 		flagSet.CommandLine.Var(&EnumVar{allowedTypes, field}, short, usage)
-		flagSet.flagKeys.Set(short, flagData)
+		flagSet.setFlagKey(short, flagData)
 	}
 	flagSet.CommandLine.Var(&EnumVar{allowedTypes, field}, long, usage)
-	flagSet.flagKeys.Set(long, flagData)
+	flagSet.setFlagKey(long, flagData)
 	return flagData
 }
 
@@ -494,16 +2072,16 @@ func (flagSet *FlagSet) EnumSliceVar(field *[]string, long string, defaultValues
 
 // EnumVarP adds a enum flag with a shortname and longname
 func (flagSet *FlagSet) EnumSliceVarP(field *[]string, long, short string, defaultValues []EnumVariable, usage string, allowedTypes AllowdTypes) *FlagData {
+	if len(defaultValues) == 0 {
+		panic(fmt.Errorf("undefined default value for flag -%v", long))
+	}
 	var defaults []string
-	for k, v := range allowedTypes {
-		for _, defaultValue := range defaultValues {
-			if v == defaultValue {
-				defaults = append(defaults, k)
-			}
+	for _, defaultValue := range defaultValues {
+		defaultKey, ok := allowedTypes.key(defaultValue)
+		if !ok {
+			panic(fmt.Errorf("invalid default value %d for flag -%v: allowed values are %v", defaultValue, long, allowedTypes.String()))
 		}
-	}
-	if len(defaults) == 0 {
-		panic("undefined default value")
+		defaults = append(defaults, defaultKey)
 	}
 
 	*field = defaults
@@ -512,21 +2090,28 @@ func (flagSet *FlagSet) EnumSliceVarP(field *[]string, long, short string, defau
 		long:         long,
 		defaultValue: strings.Join(*field, ","),
 	}
+	if !flagSet.checkDuplicateFlag(long, short) {
+		return flagData
+	}
 	if short != "" {
 		flagData.short = short
 		flagSet.CommandLine.Var(&EnumSliceVar{allowedTypes, field}, short, usage)
-		flagSet.flagKeys.Set(short, flagData)
+		flagSet.setFlagKey(short, flagData)
 	}
 	flagSet.CommandLine.Var(&EnumSliceVar{allowedTypes, field}, long, usage)
-	flagSet.flagKeys.Set(long, flagData)
+	flagSet.setFlagKey(long, flagData)
 	return flagData
 }
 
 func (flagSet *FlagSet) usageFunc() {
 	var helpAsked bool
 
+	args := flagSet.args
+	if args == nil {
+		args = os.Args[1:]
+	}
 	// Only show help usage if asked by user
-	for _, arg := range os.Args {
+	for _, arg := range args {
 		argStripped := strings.Trim(arg, "-")
 		if argStripped == "h" || argStripped == "help" {
 			helpAsked = true
@@ -535,34 +2120,57 @@ func (flagSet *FlagSet) usageFunc() {
 	if !helpAsked {
 		return
 	}
+	flagSet.writeUsage(flagSet.outputWriter(), args)
+}
+
+// UsageString returns the usage printed for -h (description, commands,
+// flags by group and custom help text) without printing it or exiting.
+func (flagSet *FlagSet) UsageString() string {
+	var builder strings.Builder
+	flagSet.writeUsage(&builder, nil)
+	return builder.String()
+}
 
-	cliOutput := flagSet.CommandLine.Output()
+// writeUsage writes the usage to the writer. A group or flag name given
+// after the help flag in args (ex: -h output) limits the usage to it.
+func (flagSet *FlagSet) writeUsage(cliOutput io.Writer, args []string) {
 	fmt.Fprintf(cliOutput, "%s\n\n", flagSet.description)
-	fmt.Fprintf(cliOutput, "Usage:\n  %s [flags]\n\n", os.Args[0])
+	if len(flagSet.commands) > 0 {
+		fmt.Fprintf(cliOutput, "Usage:\n  %s [flags] [command] [command flags]\n\n", flagSet.CommandLine.Name())
+		flagSet.usageCommands(cliOutput)
+	} else {
+		fmt.Fprintf(cliOutput, "Usage:\n  %s [flags]\n\n", flagSet.CommandLine.Name())
+	}
 	fmt.Fprintf(cliOutput, "Flags:\n")
 
 	writer := tabwriter.NewWriter(cliOutput, 0, 0, 1, ' ', 0)
 
 	// If a user has specified a group with help, and we have groups, return with the tool's usage function
-	if len(flagSet.groups) > 0 && len(os.Args) == 3 {
-		group := flagSet.getGroupbyName(strings.ToLower(os.Args[2]))
+	hasGroups := len(flagSet.groups) > 0 || flagSet.metaGroup
+	if hasGroups && len(args) == 2 {
+		group := flagSet.getGroupbyName(strings.ToLower(args[1]))
 		if group.name != "" {
 			flagSet.displayGroupUsageFunc(newUniqueDeduper(), group, cliOutput, writer)
 			return
 		}
-		flag := flagSet.getFlagByName(os.Args[2])
+		flag := flagSet.getFlagByName(args[1])
 		if flag != nil {
-			flagSet.displaySingleFlagUsageFunc(os.Args[2], flag, cliOutput, writer)
+			flagSet.displaySingleFlagUsageFunc(args[1], flag, cliOutput, writer)
 			return
 		}
 	}
 
-	if len(flagSet.groups) > 0 {
+	if hasGroups {
 		flagSet.usageFuncForGroups(cliOutput, writer)
 	} else {
 		flagSet.usageFuncInternal(writer)
 	}
 
+	// subcommands only render their own flags with a reference to the parent ones
+	if flagSet.parentName != "" {
+		fmt.Fprintf(cliOutput, "\nGlobal Flags:\n  run '%s -h' for the global flags\n", flagSet.parentName)
+	}
+
 	// If there is a custom help text specified, print it
 	if !isEmpty(flagSet.customHelpText) {
 		fmt.Fprintf(cliOutput, "\n%s\n", flagSet.customHelpText)
@@ -575,9 +2183,50 @@ func (flagSet *FlagSet) getGroupbyName(name string) groupData {
 			return group
 		}
 	}
+	if flagSet.metaGroup && strings.EqualFold(metaGroup.name, name) {
+		return metaGroup
+	}
 	return groupData{}
 }
 
+// Lookup returns the flag for a short or long name. Both names of a flag
+// resolve to the same flag, registered with the long name if present.
+func (flagSet *FlagSet) Lookup(name string) *flag.Flag {
+	flagData, ok := flagSet.flagKeys.values[name]
+	if !ok {
+		return nil
+	}
+	return flagSet.CommandLine.Lookup(flagData.canonicalName())
+}
+
+// Remove deregisters a flag by its short, long or alias name along with
+// its other names, so it is no longer parsed, listed in usage or part of
+// its group. The parse state of the command line is reset, so it should
+// be called before Parse.
+func (flagSet *FlagSet) Remove(name string) error {
+	flagData, ok := flagSet.flagKeys.values[name]
+	if !ok {
+		if _, ok := flagSet.configOnlyKeys.values[name]; !ok {
+			return fmt.Errorf("no such flag -%v", name)
+		}
+		flagSet.configOnlyKeys.delete(name)
+		return nil
+	}
+	var names []string
+	flagSet.flagKeys.forEach(func(key string, data *FlagData) {
+		if data == flagData {
+			names = append(names, key)
+		}
+	})
+	for _, key := range names {
+		flagSet.flagKeys.delete(key)
+	}
+	delete(flagSet.configFlags, flagData)
+	// names include the aliases, registered like short and long names
+	flagSet.CommandLine = copyCommandLine(flagSet.CommandLine, names...)
+	return nil
+}
+
 func (flagSet *FlagSet) getFlagByName(name string) *FlagData {
 	var flagData *FlagData
 	flagSet.flagKeys.forEach(func(key string, data *FlagData) {
@@ -594,13 +2243,36 @@ func (flagSet *FlagSet) getFlagByName(name string) *FlagData {
 	return flagData
 }
 
+// SetSortFlags sets whether flags are ordered alphabetically by long name
+// within each group in usage, MarkdownDoc and SchemaJSON. Group order is kept
+// and flags are in registration order by default.
+func (flagSet *FlagSet) SetSortFlags(sortFlags bool) {
+	flagSet.sortFlags = sortFlags
+}
+
+// forEachUsageFlag iterates the flag keys in registration order, or
+// alphabetically by canonical name if sorting is enabled
+func (flagSet *FlagSet) forEachUsageFlag(fn func(key string, data *FlagData)) {
+	if !flagSet.sortFlags {
+		flagSet.flagKeys.forEach(fn)
+		return
+	}
+	keys := append([]string(nil), flagSet.flagKeys.keys...)
+	sort.SliceStable(keys, func(i, j int) bool {
+		return flagSet.flagKeys.values[keys[i]].canonicalName() < flagSet.flagKeys.values[keys[j]].canonicalName()
+	})
+	for _, key := range keys {
+		fn(key, flagSet.flagKeys.values[key])
+	}
+}
+
 // usageFuncInternal prints usage for command line flags
 func (flagSet *FlagSet) usageFuncInternal(writer *tabwriter.Writer) {
 	uniqueDeduper := newUniqueDeduper()
 
-	flagSet.flagKeys.forEach(func(key string, data *FlagData) {
+	flagSet.forEachUsageFlag(func(key string, data *FlagData) {
 		if currentFlag := flagSet.CommandLine.Lookup(key); currentFlag != nil {
-			if !uniqueDeduper.isUnique(data) {
+			if flagSet.isHidden(data) || !uniqueDeduper.isUnique(data) {
 				return
 			}
 			result := createUsageString(data, currentFlag)
@@ -628,6 +2300,12 @@ func (flagSet *FlagSet) usageFuncForGroups(cliOutput io.Writer, writer *tabwrite
 		}
 		writer.Flush()
 	}
+	if flagSet.metaGroup {
+		if len(otherOptions) > 0 {
+			fmt.Fprintf(cliOutput, "\n")
+		}
+		flagSet.displayGroupUsageFunc(uniqueDeduper, metaGroup, cliOutput, writer)
+	}
 }
 
 // displayGroupUsageFunc displays usage for a group
@@ -635,8 +2313,11 @@ func (flagSet *FlagSet) displayGroupUsageFunc(uniqueDeduper *uniqueDeduper, grou
 	fmt.Fprintf(cliOutput, "%s:\n", normalizeGroupDescription(group.description))
 
 	var otherOptions []string
-	flagSet.flagKeys.forEach(func(key string, data *FlagData) {
+	flagSet.forEachUsageFlag(func(key string, data *FlagData) {
 		if currentFlag := flagSet.CommandLine.Lookup(key); currentFlag != nil {
+			if flagSet.isHidden(data) {
+				return
+			}
 			if data.group == "" {
 				if !uniqueDeduper.isUnique(data) {
 					return
@@ -656,13 +2337,13 @@ func (flagSet *FlagSet) displayGroupUsageFunc(uniqueDeduper *uniqueDeduper, grou
 		}
 	})
 	writer.Flush()
-	fmt.Printf("\n")
+	fmt.Fprintln(cliOutput)
 	return otherOptions
 }
 
 // displaySingleFlagUsageFunc displays usage for a single flag
 func (flagSet *FlagSet) displaySingleFlagUsageFunc(name string, data *FlagData, cliOutput io.Writer, writer *tabwriter.Writer) {
-	if currentFlag := flagSet.CommandLine.Lookup(name); currentFlag != nil {
+	if currentFlag := flagSet.CommandLine.Lookup(name); currentFlag != nil && !flagSet.isHidden(data) {
 		result := createUsageString(data, currentFlag)
 		fmt.Fprint(writer, result, "\n")
 		writer.Flush()
@@ -698,28 +2379,64 @@ func createUsageString(data *FlagData, currentFlag *flag.Flag) string {
 }
 
 func createUsageDefaultValue(data *FlagData, currentFlag *flag.Flag, valueType reflect.Type) string {
-	if !isZeroValue(currentFlag, currentFlag.DefValue) {
-		defaultValueTemplate := " (default "
-		switch valueType.String() { // ugly hack because "flag.stringValue" is not exported from the parent library
-		case "*flag.stringValue":
-			defaultValueTemplate += "%q"
-		default:
-			defaultValueTemplate += "%v"
-		}
-		defaultValueTemplate += ")"
-		return fmt.Sprintf(defaultValueTemplate, data.defaultValue)
+	if defaultValue := usageDefaultValue(data, currentFlag, valueType); defaultValue != "" {
+		return " (default " + defaultValue + ")"
 	}
 	return ""
 }
 
+// usageDefaultValue returns the formatted default value of a flag or
+// an empty string if the default is the zero value
+func usageDefaultValue(data *FlagData, currentFlag *flag.Flag, valueType reflect.Type) string {
+	if isZeroValue(currentFlag, currentFlag.DefValue) {
+		return ""
+	}
+	if data.secret {
+		return secretMask
+	}
+	switch valueType.String() { // ugly hack because "flag.stringValue" is not exported from the parent library
+	case "*flag.stringValue":
+		return fmt.Sprintf("%q", data.defaultValue)
+	default:
+		return fmt.Sprintf("%v", data.defaultValue)
+	}
+}
+
+// displayTyper is implemented by custom flag values displayed
+// with a specific type in usage (ex: "string")
+type displayTyper interface {
+	displayType() string
+}
+
 func createUsageTypeAndDescription(currentFlag *flag.Flag, valueType reflect.Type) string {
 	var result string
 
+	flagDisplayType, usage := usageTypeAndDescription(currentFlag, valueType)
+	if len(flagDisplayType) > 0 {
+		result += " " + flagDisplayType
+	}
+
+	result += "\t\t"
+	result += strings.ReplaceAll(usage, "\n", "\n"+strings.Repeat(" ", 4)+"\t")
+	return result
+}
+
+// usageTypeAndDescription returns the display type (empty for bool flags) and usage of a flag
+func usageTypeAndDescription(currentFlag *flag.Flag, valueType reflect.Type) (string, string) {
 	flagDisplayType, usage := flag.UnquoteUsage(currentFlag)
+	if _, ok := currentFlag.Value.(*countValue); ok {
+		// count flags are bool flags for parsing and have no display type by default
+		return "count", usage + " (repeatable)"
+	}
+	if _, ok := currentFlag.Value.(*BoolString); ok {
+		// bool or string flags take their optional value with the -flag=value form
+		return "[=string]", usage
+	}
 	if len(flagDisplayType) > 0 {
 		if flagDisplayType == "value" { // hardcoded in the goflags library
-			switch valueType.Kind() {
-			case reflect.Ptr:
+			if typer, ok := currentFlag.Value.(displayTyper); ok {
+				flagDisplayType = typer.displayType()
+			} else if valueType.Kind() == reflect.Ptr {
 				pointerTypeElement := valueType.Elem()
 				switch pointerTypeElement.Kind() {
 				case reflect.Slice, reflect.Array:
@@ -732,12 +2449,8 @@ func createUsageTypeAndDescription(currentFlag *flag.Flag, valueType reflect.Typ
 				}
 			}
 		}
-		result += " " + flagDisplayType
 	}
-
-	result += "\t\t"
-	result += strings.ReplaceAll(usage, "\n", "\n"+strings.Repeat(" ", 4)+"\t")
-	return result
+	return flagDisplayType, usage
 }
 
 func createUsageFlagNames(data *FlagData) string {
@@ -754,7 +2467,7 @@ func createUsageFlagNames(data *FlagData) string {
 	addValidParam(data.long)
 
 	if len(validFlags) == 0 {
-		panic("CLI arguments cannot be empty.")
+		panic(fmt.Sprintf("CLI arguments cannot be empty (flag with usage %q).", data.usage))
 	}
 
 	flagNames += strings.Join(validFlags, ", ")
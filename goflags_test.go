@@ -2,9 +2,12 @@ package goflags
 
 import (
 	"bytes"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"os"
+	"path/filepath"
 	"reflect"
 	"strconv"
 	"strings"
@@ -39,6 +42,127 @@ func TestGenerateDefaultConfig(t *testing.T) {
 	tearDown(t.Name())
 }
 
+func TestGenerateDefaultConfigCommaForm(t *testing.T) {
+	flagSet := NewFlagSet()
+
+	options := CommaSeparatedStringSliceOptions
+	options.ConfigCommaForm = true
+
+	var data, data2 StringSlice
+	flagSet.StringSliceVar(&data, "comma-slice", []string{"item1", "item2"}, "Comma slice flag example value", options)
+	flagSet.StringSliceVar(&data2, "slice", []string{"item1", "item2"}, "String slice flag example value", StringSliceOptions)
+	defaultConfig := string(flagSet.generateDefaultConfig())
+
+	require.Contains(t, defaultConfig, "#comma-slice: item1,item2\n", "could not render comma form")
+	require.Contains(t, defaultConfig, `#slice: ["item1", "item2"]`, "could not keep list form")
+	tearDown(t.Name())
+}
+
+func TestGenerateDefaultConfigComment(t *testing.T) {
+	flagSet := NewFlagSet()
+
+	var data, data2 string
+	flagSet.StringVar(&data, "rate", "150", "Rate example").ConfigComment("maximum requests per second\n\nlower it for fragile targets")
+	flagSet.StringVar(&data2, "test", "test-default-value", "Default value for a test flag example")
+	defaultConfig := string(flagSet.generateDefaultConfig())
+
+	require.Contains(t, defaultConfig, "# maximum requests per second\n#\n# lower it for fragile targets\n#rate: 150\n", "could not render config comment")
+	require.NotContains(t, defaultConfig, "# rate example")
+	require.Contains(t, defaultConfig, "# default value for a test flag example\n#test: test-default-value", "could not keep usage comment")
+	tearDown(t.Name())
+}
+
+func TestVarEnvDefaults(t *testing.T) {
+	t.Run("env-set", func(t *testing.T) {
+		t.Setenv("GOFLAGS_TEST_TOKEN", "env-token")
+		t.Setenv("GOFLAGS_TEST_THREADS", "50")
+		t.Setenv("GOFLAGS_TEST_SILENT", "true")
+		t.Setenv("GOFLAGS_TEST_TIMEOUT", "30s")
+
+		flagSet := NewFlagSet()
+		var token string
+		var threads int
+		var silent bool
+		var timeout time.Duration
+		flagSet.StringVarEnv(&token, "token", "", "fallback-token", "GOFLAGS_TEST_TOKEN", "Token example")
+		flagSet.IntVarEnv(&threads, "threads", "", 10, "GOFLAGS_TEST_THREADS", "Threads example")
+		flagSet.BoolVarEnv(&silent, "silent", "", false, "GOFLAGS_TEST_SILENT", "Silent example")
+		flagSet.DurationVarEnv(&timeout, "timeout", "", 5*time.Second, "GOFLAGS_TEST_TIMEOUT", "Timeout example")
+
+		require.Equal(t, "env-token", token)
+		require.Equal(t, 50, threads)
+		require.True(t, silent)
+		require.Equal(t, 30*time.Second, timeout)
+
+		defaultConfig := string(flagSet.generateDefaultConfig())
+		require.Contains(t, defaultConfig, "#token: env-token")
+		require.Contains(t, defaultConfig, "#threads: 50")
+		require.Contains(t, defaultConfig, "#silent: true")
+		require.Contains(t, defaultConfig, "#timeout: 30s")
+		tearDown(t.Name())
+	})
+
+	t.Run("env-unset", func(t *testing.T) {
+		t.Setenv("GOFLAGS_TEST_THREADS", "not-a-number")
+
+		flagSet := NewFlagSet()
+		var token string
+		var threads int
+		var silent bool
+		var timeout time.Duration
+		flagSet.StringVarEnv(&token, "token", "", "fallback-token", "GOFLAGS_TEST_MISSING_TOKEN", "Token example")
+		flagSet.IntVarEnv(&threads, "threads", "", 10, "GOFLAGS_TEST_THREADS", "Threads example")
+		flagSet.BoolVarEnv(&silent, "silent", "", false, "GOFLAGS_TEST_MISSING_SILENT", "Silent example")
+		flagSet.DurationVarEnv(&timeout, "timeout", "", 5*time.Second, "GOFLAGS_TEST_MISSING_TIMEOUT", "Timeout example")
+
+		require.Equal(t, "fallback-token", token)
+		require.Equal(t, 10, threads)
+		require.False(t, silent)
+		require.Equal(t, 5*time.Second, timeout)
+
+		defaultConfig := string(flagSet.generateDefaultConfig())
+		require.Contains(t, defaultConfig, "#token: fallback-token")
+		require.Contains(t, defaultConfig, "#threads: 10")
+		tearDown(t.Name())
+	})
+}
+
+func TestGenerateGroupedDefaultConfig(t *testing.T) {
+	flagSet := NewFlagSet()
+
+	example := `# generated by https://github.com/projectdiscovery/goflags
+
+# INPUT
+
+# string slice flag example value
+#slice: ["item1", "item2"]
+
+# OUTPUT
+
+# default value for a test flag example
+#test: test-default-value
+
+# OTHER OPTIONS
+
+# int value example
+#int-value: 10`
+
+	var data string
+	var data2 StringSlice
+	var data3 int
+	flagSet.SetGroup("input", "Input")
+	flagSet.SetGroup("output", "Output")
+	flagSet.SetGroup("unused", "Unused")
+	flagSet.StringVar(&data, "test", "test-default-value", "Default value for a test flag example").Group("output")
+	flagSet.IntVar(&data3, "int-value", 10, "Int value example")
+	flagSet.StringSliceVar(&data2, "slice", []string{"item1", "item2"}, "String slice flag example value", StringSliceOptions).Group("input")
+	defaultConfig := string(flagSet.generateDefaultConfig())
+	parts := strings.SplitN(defaultConfig, "\n", 2)
+
+	require.Equal(t, example, parts[1], "could not get correct grouped default config")
+	tearDown(t.Name())
+}
+
 func TestConfigFileDataTypes(t *testing.T) {
 	flagSet := NewFlagSet()
 	var data string
@@ -80,6 +204,540 @@ duration-value: 1h`
 	tearDown(t.Name())
 }
 
+func TestConfigFileStringBool(t *testing.T) {
+	flagSet := NewFlagSet()
+	var trueValue, oneValue, yesValue, falseValue, zeroValue, noValue bool
+
+	flagSet.BoolVar(&trueValue, "true-value", false, "Quoted true example")
+	flagSet.BoolVar(&oneValue, "one-value", false, "Quoted 1 example")
+	flagSet.BoolVar(&yesValue, "yes-value", false, "Quoted yes example")
+	flagSet.BoolVar(&falseValue, "false-value", true, "Quoted false example")
+	flagSet.BoolVar(&zeroValue, "zero-value", true, "Quoted 0 example")
+	flagSet.BoolVar(&noValue, "no-value", true, "Quoted no example")
+
+	configFileData := `
+true-value: "true"
+one-value: "1"
+yes-value: "Yes"
+false-value: "false"
+zero-value: "0"
+no-value: "no"`
+	err := os.WriteFile("test.yaml", []byte(configFileData), permissionutil.ConfigFilePermission)
+	require.Nil(t, err, "could not write temporary config")
+	defer os.Remove("test.yaml")
+
+	err = flagSet.MergeConfigFile("test.yaml")
+	require.Nil(t, err, "could not merge temporary config")
+
+	require.True(t, trueValue, "could not coerce quoted true")
+	require.True(t, oneValue, "could not coerce quoted 1")
+	require.True(t, yesValue, "could not coerce quoted yes")
+	require.False(t, falseValue, "could not coerce quoted false")
+	require.False(t, zeroValue, "could not coerce quoted 0")
+	require.False(t, noValue, "could not coerce quoted no")
+
+	tearDown(t.Name())
+}
+
+func TestConfigFileOnOffBool(t *testing.T) {
+	flagSet := NewFlagSet()
+	var verbose, silent, color bool
+	flagSet.BoolVar(&verbose, "verbose", false, "Verbose example")
+	flagSet.BoolVar(&silent, "silent", true, "Silent example")
+	flagSet.BoolVar(&color, "color", false, "Color example")
+
+	err := flagSet.MergeConfigReader(strings.NewReader("verbose: on\nsilent: off\ncolor: \"ON\""), "yaml")
+	require.Nil(t, err, "could not merge config")
+	require.True(t, verbose, "could not coerce on")
+	require.False(t, silent, "could not coerce off")
+	require.True(t, color, "could not coerce quoted ON")
+	tearDown(t.Name())
+}
+
+func TestConfigFileStringSliceFromFile(t *testing.T) {
+	flagSet := NewFlagSet()
+	var fileData StringSlice
+	var inlineData StringSlice
+
+	flagSet.StringSliceVar(&fileData, "file-value", nil, "String slice read from file example", FileStringSliceOptions)
+	flagSet.StringSliceVar(&inlineData, "inline-value", nil, "String slice inline example", FileStringSliceOptions)
+
+	err := os.WriteFile("values.txt", []byte("value1\nvalue2"), permissionutil.ConfigFilePermission)
+	require.Nil(t, err, "could not write temporary values file")
+	defer os.Remove("values.txt")
+
+	configFileData := `
+file-value: values.txt
+inline-value:
+ - values.txt
+ - value3`
+	err = os.WriteFile("test.yaml", []byte(configFileData), permissionutil.ConfigFilePermission)
+	require.Nil(t, err, "could not write temporary config")
+	defer os.Remove("test.yaml")
+
+	err = flagSet.MergeConfigFile("test.yaml")
+	require.Nil(t, err, "could not merge temporary config")
+
+	require.Equal(t, StringSlice{"value1", "value2"}, fileData, "could not read string slice from file")
+	require.Equal(t, StringSlice{"values.txt", "value3"}, inlineData, "could not get inline string slice")
+
+	tearDown(t.Name())
+}
+
+func TestConfigFileInclude(t *testing.T) {
+	t.Run("simple-include", func(t *testing.T) {
+		flagSet := NewFlagSet()
+		var data string
+		var data2 int
+		flagSet.StringVar(&data, "string-value", "", "String value example")
+		flagSet.IntVar(&data2, "int-value", 0, "Int value example")
+
+		dir := t.TempDir()
+		err := os.MkdirAll(filepath.Join(dir, "conf.d"), os.ModePerm)
+		require.Nil(t, err, "could not create include directory")
+		err = os.WriteFile(filepath.Join(dir, "conf.d", "base.yaml"), []byte("string-value: base\nint-value: 10"), permissionutil.ConfigFilePermission)
+		require.Nil(t, err, "could not write included config")
+		err = os.WriteFile(filepath.Join(dir, "config.yaml"), []byte("include:\n - conf.d/base.yaml\nstring-value: main"), permissionutil.ConfigFilePermission)
+		require.Nil(t, err, "could not write config")
+
+		err = flagSet.MergeConfigFile(filepath.Join(dir, "config.yaml"))
+		require.Nil(t, err, "could not merge config with include")
+		require.Equal(t, "main", data, "included value should be overridden by the including file")
+		require.Equal(t, 10, data2, "could not get included value")
+		tearDown(t.Name())
+	})
+
+	t.Run("cyclic-include", func(t *testing.T) {
+		flagSet := NewFlagSet()
+		var data string
+		flagSet.StringVar(&data, "string-value", "", "String value example")
+
+		dir := t.TempDir()
+		err := os.WriteFile(filepath.Join(dir, "a.yaml"), []byte("include: b.yaml\nstring-value: a"), permissionutil.ConfigFilePermission)
+		require.Nil(t, err, "could not write config")
+		err = os.WriteFile(filepath.Join(dir, "b.yaml"), []byte("include: a.yaml"), permissionutil.ConfigFilePermission)
+		require.Nil(t, err, "could not write config")
+
+		err = flagSet.MergeConfigFile(filepath.Join(dir, "a.yaml"))
+		require.ErrorContains(t, err, "config include cycle")
+		require.Empty(t, data, "config with include cycle should not be applied")
+		tearDown(t.Name())
+	})
+}
+
+func TestConfigKeyNormalization(t *testing.T) {
+	flagSet := NewFlagSet()
+	flagSet.SetConfigStrict(true)
+	var retries, timeout int
+	var headers StringSlice
+	flagSet.IntVar(&retries, "max-retries", 1, "Max retries example")
+	flagSet.IntVar(&timeout, "read-timeout", 10, "Read timeout example")
+	flagSet.StringSliceVar(&headers, "custom-header", nil, "Header example", StringSliceOptions)
+
+	configFileData := `
+max_retries: 3
+read_timeout: 20
+read-timeout: 30
+custom_header:
+ - a:b`
+	err := os.WriteFile("test.yaml", []byte(configFileData), permissionutil.ConfigFilePermission)
+	require.Nil(t, err, "could not write temporary config")
+	defer os.Remove("test.yaml")
+
+	err = flagSet.MergeConfigFile("test.yaml")
+	require.Nil(t, err, "could not merge temporary config")
+	require.Equal(t, 3, retries, "could not get value of underscore key")
+	require.Equal(t, 30, timeout, "exact key should take precedence")
+	require.Equal(t, StringSlice{"a:b"}, headers)
+	tearDown(t.Name())
+}
+
+func TestMergeConfigFileIfExists(t *testing.T) {
+	dir := t.TempDir()
+	flagSet := NewFlagSet()
+	var data string
+	flagSet.StringVar(&data, "string-value", "default", "String value example")
+
+	err := flagSet.MergeConfigFileIfExists(filepath.Join(dir, "missing.yaml"))
+	require.Nil(t, err, "missing file should be skipped")
+	require.Equal(t, "default", data)
+
+	malformed := filepath.Join(dir, "malformed.yaml")
+	require.Nil(t, os.WriteFile(malformed, []byte("string-value: [unclosed"), os.ModePerm))
+	err = flagSet.MergeConfigFileIfExists(malformed)
+	require.NotNil(t, err, "malformed file should return an error")
+
+	valid := filepath.Join(dir, "valid.yaml")
+	require.Nil(t, os.WriteFile(valid, []byte("string-value: config"), os.ModePerm))
+	err = flagSet.MergeConfigFileIfExists(valid)
+	require.Nil(t, err)
+	require.Equal(t, "config", data)
+	tearDown(t.Name())
+}
+
+func TestDiffConfigs(t *testing.T) {
+	dir := t.TempDir()
+	flagSet := NewFlagSet()
+	var output string
+	var timeout, retries int
+	var silent bool
+	var targets StringSlice
+	flagSet.StringVarP(&output, "output", "o", "", "output file")
+	flagSet.IntVar(&timeout, "timeout", 10, "timeout in seconds")
+	flagSet.IntVar(&retries, "max-retries", 1, "number of retries")
+	flagSet.BoolVar(&silent, "silent", false, "silent mode")
+	flagSet.StringSliceVar(&targets, "targets", nil, "targets", StringSliceOptions)
+
+	configA := filepath.Join(dir, "a.yaml")
+	require.Nil(t, os.WriteFile(configA, []byte("o: a.txt\ntimeout: 10\nmax_retries: 2\nsilent: true\ntargets: [a, b]\nplugin: x"), os.ModePerm))
+	configB := filepath.Join(dir, "b.yaml")
+	require.Nil(t, os.WriteFile(configB, []byte("output: b.txt\ntimeout: 10\nmax-retries: 3\ntargets: [a, b]\nplugin: y"), os.ModePerm))
+
+	diffs, err := flagSet.DiffConfigs(configA, configB)
+	require.Nil(t, err)
+	require.Equal(t, []ConfigDiff{
+		{Key: "output", Kind: ConfigDiffChanged, Old: "a.txt", New: "b.txt"},
+		{Key: "max-retries", Kind: ConfigDiffChanged, Old: 2, New: 3},
+		{Key: "silent", Kind: ConfigDiffRemoved, Old: true},
+	}, diffs, "unchanged and unknown keys should be ignored")
+
+	diffs, err = flagSet.DiffConfigs(configB, configA)
+	require.Nil(t, err)
+	require.Equal(t, ConfigDiff{Key: "silent", Kind: ConfigDiffAdded, New: true}, diffs[2])
+
+	_, err = flagSet.DiffConfigs(configA, filepath.Join(dir, "missing.yaml"))
+	require.NotNil(t, err)
+	require.Equal(t, "", output, "configs should not be merged")
+	tearDown(t.Name())
+}
+
+func TestConfigPreprocessor(t *testing.T) {
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "config.yaml")
+	require.Nil(t, os.WriteFile(configFile, []byte("legacy-output: out.txt\nthreads: 5"), os.ModePerm))
+
+	t.Run("rename", func(t *testing.T) {
+		flagSet := NewFlagSet()
+		var output string
+		var threads int
+		flagSet.StringVar(&output, "output", "", "output file")
+		flagSet.IntVar(&threads, "threads", 1, "number of threads")
+		flagSet.SetConfigPreprocessor(func(data map[string]interface{}) error {
+			if item, ok := data["legacy-output"]; ok {
+				data["output"] = item
+				delete(data, "legacy-output")
+			}
+			return nil
+		})
+
+		require.Nil(t, flagSet.MergeConfigFile(configFile))
+		require.Equal(t, "out.txt", output, "renamed key should match the flag")
+		require.Equal(t, 5, threads)
+		require.Empty(t, flagSet.ConfigExtras())
+		tearDown(t.Name())
+	})
+
+	t.Run("diff", func(t *testing.T) {
+		flagSet := NewFlagSet()
+		var output string
+		var threads int
+		flagSet.StringVar(&output, "output", "", "output file")
+		flagSet.IntVar(&threads, "threads", 1, "number of threads")
+		flagSet.SetConfigPreprocessor(func(data map[string]interface{}) error {
+			if item, ok := data["legacy-output"]; ok {
+				data["output"] = item
+				delete(data, "legacy-output")
+			}
+			return nil
+		})
+		otherConfig := filepath.Join(dir, "other.yaml")
+		require.Nil(t, os.WriteFile(otherConfig, []byte("output: other.txt\nthreads: 5"), os.ModePerm))
+
+		diffs, err := flagSet.DiffConfigs(configFile, otherConfig)
+		require.Nil(t, err)
+		require.Equal(t, []ConfigDiff{
+			{Key: "output", Kind: ConfigDiffChanged, Old: "out.txt", New: "other.txt"},
+		}, diffs, "renamed key should be compared")
+		tearDown(t.Name())
+	})
+
+	t.Run("reject", func(t *testing.T) {
+		flagSet := NewFlagSet()
+		var threads int
+		flagSet.IntVar(&threads, "threads", 1, "number of threads")
+		flagSet.SetConfigPreprocessor(func(data map[string]interface{}) error {
+			if _, ok := data["legacy-output"]; ok {
+				return errors.New("legacy-output is no longer supported")
+			}
+			return nil
+		})
+
+		err := flagSet.MergeConfigFile(configFile)
+		require.EqualError(t, err, "invalid config "+configFile+": legacy-output is no longer supported")
+		require.Equal(t, 1, threads, "rejected config should not be applied")
+		tearDown(t.Name())
+	})
+}
+
+func TestMergeConfigReader(t *testing.T) {
+	for format, content := range map[string]string{
+		"yaml": "string-value: test\nint-value: 543\nbool-value: true\nslice-value:\n - a\n - b",
+		"json": `{"string-value": "test", "int-value": 543, "bool-value": true, "slice-value": ["a", "b"]}`,
+		"toml": "string-value = \"test\"\nint-value = 543\nbool-value = true\nslice-value = [\"a\", \"b\"]",
+	} {
+		t.Run(format, func(t *testing.T) {
+			flagSet := NewFlagSet()
+			var data string
+			var data2 int
+			var data3 bool
+			var data4 StringSlice
+			flagSet.StringVar(&data, "string-value", "", "String value example")
+			flagSet.IntVar(&data2, "int-value", 0, "Int value example")
+			flagSet.BoolVar(&data3, "bool-value", false, "Bool value example")
+			flagSet.StringSliceVar(&data4, "slice-value", nil, "String slice value example", StringSliceOptions)
+
+			err := flagSet.MergeConfigReader(bytes.NewReader([]byte(content)), format)
+			require.Nil(t, err, "could not merge config reader")
+			require.Equal(t, "test", data)
+			require.Equal(t, 543, data2)
+			require.True(t, data3)
+			require.Equal(t, StringSlice{"a", "b"}, data4)
+			tearDown(t.Name())
+		})
+	}
+
+	flagSet := NewFlagSet()
+	err := flagSet.MergeConfigReader(bytes.NewReader([]byte("a=b")), "ini")
+	require.EqualError(t, err, `unsupported config format "ini"`)
+	tearDown(t.Name())
+}
+
+func TestMergeConfigProfile(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	content := "default:\n  threads: 10\n  target: default.example.com\n  verbose: true\ndev:\n  target: dev.example.com\nprod:\n  target: prod.example.com\n  threads: 50\n"
+	require.Nil(t, os.WriteFile(configFile, []byte(content), os.ModePerm))
+
+	flagSet := NewFlagSet()
+	var target string
+	var threads int
+	var verbose bool
+	flagSet.StringVar(&target, "target", "", "target")
+	flagSet.IntVar(&threads, "threads", 1, "threads")
+	flagSet.BoolVar(&verbose, "verbose", false, "verbose")
+
+	err := flagSet.MergeConfigProfile(configFile, "prod")
+	require.Nil(t, err, "could not merge config profile")
+	require.Equal(t, "prod.example.com", target)
+	require.Equal(t, 50, threads)
+	require.True(t, verbose, "default section was not inherited")
+
+	err = flagSet.MergeConfigProfile(configFile, "staging")
+	require.EqualError(t, err, `profile "staging" not found in `+configFile)
+	tearDown(t.Name())
+}
+
+func TestConfigExtras(t *testing.T) {
+	flagSet := NewFlagSet()
+	var target string
+	flagSet.StringVar(&target, "target", "", "target")
+
+	config := "target: example.com\nplugin:\n  name: custom\n  enabled: true\nextra-key: value"
+	err := flagSet.MergeConfigReader(strings.NewReader(config), "yaml")
+	require.Nil(t, err)
+	require.Equal(t, "example.com", target)
+	require.Equal(t, map[string]interface{}{
+		"plugin":    map[string]interface{}{"name": "custom", "enabled": true},
+		"extra-key": "value",
+	}, flagSet.ConfigExtras())
+
+	// extras are replaced by the last merged config
+	err = flagSet.MergeConfigReader(strings.NewReader("target: other.com"), "yaml")
+	require.Nil(t, err)
+	require.Empty(t, flagSet.ConfigExtras())
+	tearDown(t.Name())
+}
+
+func TestConfigEnvExpansion(t *testing.T) {
+	t.Setenv("GOFLAGS_TEST_API_TOKEN", "s3cr3t")
+	config := "token: ${GOFLAGS_TEST_API_TOKEN}\nheader:\n  - \"Authorization: $GOFLAGS_TEST_API_TOKEN\"\nproxy: ${GOFLAGS_TEST_UNDEFINED}"
+
+	newFlagSet := func() (*FlagSet, *string, *StringSlice, *string) {
+		flagSet := NewFlagSet()
+		var token, proxy string
+		var headers StringSlice
+		flagSet.StringVar(&token, "token", "", "token")
+		flagSet.StringSliceVar(&headers, "header", nil, "headers", StringSliceOptions)
+		flagSet.StringVar(&proxy, "proxy", "default-proxy", "proxy")
+		flagSet.SetConfigEnvExpansion(true)
+		return flagSet, &token, &headers, &proxy
+	}
+
+	flagSet, token, headers, proxy := newFlagSet()
+	err := flagSet.MergeConfigReader(strings.NewReader(config), "yaml")
+	require.Nil(t, err)
+	require.Equal(t, "s3cr3t", *token)
+	require.Equal(t, StringSlice{"Authorization: s3cr3t"}, *headers)
+	require.Equal(t, "", *proxy, "undefined variable should expand to empty")
+	tearDown(t.Name())
+
+	flagSet, _, _, _ = newFlagSet()
+	flagSet.SetConfigEnvStrict(true)
+	err = flagSet.MergeConfigReader(strings.NewReader(config), "yaml")
+	require.EqualError(t, err, "undefined environment variables in yaml config: GOFLAGS_TEST_UNDEFINED")
+	tearDown(t.Name())
+
+	// values are kept verbatim without expansion
+	flagSet = NewFlagSet()
+	var verbatim string
+	flagSet.StringVar(&verbatim, "token", "", "token")
+	err = flagSet.MergeConfigReader(strings.NewReader(config), "yaml")
+	require.Nil(t, err)
+	require.Equal(t, "${GOFLAGS_TEST_API_TOKEN}", verbatim)
+	tearDown(t.Name())
+}
+
+func TestDefaultFromConfig(t *testing.T) {
+	newFlagSet := func() (*FlagSet, *time.Duration, *int) {
+		flagSet := NewFlagSet()
+		var timeout time.Duration
+		var retries int
+		flagSet.DurationVarP(&timeout, "timeout", "t", 10*time.Second, "Timeout example").DefaultFromConfig("defaults.timeout")
+		flagSet.IntVar(&retries, "retries", 1, "Retries example").DefaultFromConfig("default-retries")
+		return flagSet, &timeout, &retries
+	}
+	writeConfig := func(t *testing.T, content string) string {
+		configFile := filepath.Join(t.TempDir(), "config.yaml")
+		err := os.WriteFile(configFile, []byte(content), permissionutil.ConfigFilePermission)
+		require.Nil(t, err, "could not write temporary config")
+		return configFile
+	}
+
+	t.Run("effective-default", func(t *testing.T) {
+		flagSet, timeout, retries := newFlagSet()
+		err := flagSet.MergeConfigFile(writeConfig(t, "defaults:\n  timeout: 30s\ndefault-retries: 3"))
+		require.Nil(t, err, "could not merge temporary config")
+		require.Equal(t, 30*time.Second, *timeout)
+		require.Equal(t, 3, *retries)
+		require.Equal(t, "30s", flagSet.CommandLine.Lookup("t").DefValue, "default value was not updated")
+
+		// the flag key of a later config overrides the config default
+		err = flagSet.MergeConfigFile(writeConfig(t, "timeout: 1m"))
+		require.Nil(t, err, "could not merge temporary config")
+		require.Equal(t, time.Minute, *timeout)
+		tearDown(t.Name())
+	})
+
+	t.Run("precedence", func(t *testing.T) {
+		flagSet, timeout, retries := newFlagSet()
+		flagSet.SetConfigStrict(true)
+		flagSet.CommandLine.SetOutput(&bytes.Buffer{})
+		_ = flagSet.CommandLine.Parse([]string{"-retries", "5"})
+
+		err := flagSet.MergeConfigFile(writeConfig(t, "defaults:\n  timeout: 30s\ntimeout: 20s\ndefault-retries: 3"))
+		require.Nil(t, err, "could not merge temporary config")
+		require.Equal(t, 20*time.Second, *timeout, "flag key should take precedence over config default")
+		require.Equal(t, 5, *retries, "command line should take precedence over config default")
+		tearDown(t.Name())
+	})
+}
+
+func TestConfigKeyOverride(t *testing.T) {
+	t.Run("legacy-key", func(t *testing.T) {
+		flagSet := NewFlagSet()
+		var data string
+		var data2 StringSlice
+		flagSet.StringVarP(&data, "rate-limit", "rl", "", "Rate limit example").ConfigKey("rate_limit")
+		flagSet.StringSliceVar(&data2, "header", nil, "Header example", StringSliceOptions).ConfigKey("headers")
+
+		configFileData := `
+rate_limit: "150"
+headers:
+ - a
+ - b`
+		err := os.WriteFile("test.yaml", []byte(configFileData), permissionutil.ConfigFilePermission)
+		require.Nil(t, err, "could not write temporary config")
+		defer os.Remove("test.yaml")
+
+		err = flagSet.MergeConfigFile("test.yaml")
+		require.Nil(t, err, "could not merge temporary config")
+
+		require.Equal(t, "150", data, "could not get value from legacy key")
+		require.Equal(t, StringSlice{"a", "b"}, data2, "could not get slice from legacy key")
+		tearDown(t.Name())
+	})
+
+	t.Run("both-keys", func(t *testing.T) {
+		flagSet := NewFlagSet()
+		var data string
+		flagSet.StringVar(&data, "rate-limit", "", "Rate limit example").ConfigKey("rate_limit")
+		output := &bytes.Buffer{}
+		flagSet.CommandLine.SetOutput(output)
+
+		configFileData := `
+rate-limit: "100"
+rate_limit: "150"`
+		err := os.WriteFile("test.yaml", []byte(configFileData), permissionutil.ConfigFilePermission)
+		require.Nil(t, err, "could not write temporary config")
+		defer os.Remove("test.yaml")
+
+		err = flagSet.MergeConfigFile("test.yaml")
+		require.Nil(t, err, "could not merge temporary config")
+
+		require.Equal(t, "150", data, "override key should take precedence")
+		require.Contains(t, output.String(), "warning: config keys \"rate_limit\" and \"rate-limit\" are both set")
+		tearDown(t.Name())
+	})
+}
+
+func TestConfigStrict(t *testing.T) {
+	t.Run("clean-config", func(t *testing.T) {
+		flagSet := NewFlagSet()
+		flagSet.SetConfigStrict(true)
+		var data string
+		var data2 string
+		var data3 StringSlice
+		flagSet.StringVarP(&data, "string-value", "sv", "", "String value example")
+		flagSet.StringVar(&data2, "rate-limit", "", "Rate limit example").ConfigKey("rate_limit")
+		flagSet.StringSliceVarConfigOnly(&data3, "config-only", nil, "Config only example")
+
+		configFileData := `
+string-value: test
+rate_limit: "150"
+config-only:
+ - a`
+		err := os.WriteFile("test.yaml", []byte(configFileData), permissionutil.ConfigFilePermission)
+		require.Nil(t, err, "could not write temporary config")
+		defer os.Remove("test.yaml")
+
+		err = flagSet.MergeConfigFile("test.yaml")
+		require.Nil(t, err, "could not merge clean config")
+		require.Equal(t, "test", data)
+		require.Equal(t, "150", data2)
+		require.Equal(t, StringSlice{"a"}, data3)
+		tearDown(t.Name())
+	})
+
+	t.Run("unknown-key", func(t *testing.T) {
+		flagSet := NewFlagSet()
+		flagSet.SetConfigStrict(true)
+		var data string
+		flagSet.StringVarP(&data, "string-value", "sv", "", "String value example")
+
+		configFileData := `
+string-value: test
+strng-value: typo`
+		err := os.WriteFile("test.yaml", []byte(configFileData), permissionutil.ConfigFilePermission)
+		require.Nil(t, err, "could not write temporary config")
+		defer os.Remove("test.yaml")
+
+		err = flagSet.MergeConfigFile("test.yaml")
+		require.NotNil(t, err, "could not get unknown key error")
+		require.Contains(t, err.Error(), "strng-value")
+		require.Empty(t, data, "config should not be applied with unknown keys")
+		tearDown(t.Name())
+	})
+}
+
 func TestUsageOrder(t *testing.T) {
 	flagSet := NewFlagSet()
 
@@ -152,33 +810,143 @@ STRING:
    -ts2 string                              String with default value example #2 (default "test-string")
    -string-with-default-value string        String with default value example (default "test-string")
    -ts, -string-with-default-value2 string  String with default value example #2 (default "test-string")
+
 STRINGSLICE:
    -slice-value string[]                       String slice flag example value
    -sv, -slice-value2 string[]                 String slice flag example value #2
    -slice-with-default-value string[]          String slice flag with default example values (default ["a", "b", "c"])
    -swdf, -slice-with-default-value2 string[]  String slice flag with default example values #2 (default ["a", "b", "c"])
+
 INTEGER:
    -int-value int                       Int value example
    -iv, -int-value2 int                 Int value example #2
    -int-with-default-value int          Int with default value example (default 12)
    -iwdv, -int-with-default-value2 int  Int with default value example #2 (default 12)
+
 BOOLEAN:
    -bool-value                       Bool value example
    -bv, -bool-value2                 Bool value example #2
    -bool-with-default-value          Bool with default value example (default true)
    -bwdv, -bool-with-default-value2  Bool with default value example #2 (default true)
+
 ENUM:
    -en, -enum-with-default-value value         Enum with default value(zero/one/two) (default zero)
    -esn, -enum-slice-with-default-value value  Enum with default value(zero/one/two) (default zero)
+
 UPDATE:
    -update                      update tool_1 to the latest released version
    -duc, -disable-update-check  disable automatic update check
+
 `
 	assert.Equal(t, expected, actual)
 
 	tearDown(t.Name())
 }
 
+func TestUsageString(t *testing.T) {
+	flagSet := NewFlagSet()
+	flagSet.SetDescription("Usage string example")
+	flagSet.SetCustomHelpText("EXAMPLES:\n  tool -t example.com")
+
+	var target, output string
+	var silent bool
+	flagSet.CreateGroup("input", "Input",
+		flagSet.StringVarP(&target, "target", "t", "", "target to scan"),
+	)
+	flagSet.CreateGroup("output", "Output",
+		flagSet.StringVarP(&output, "output", "o", "", "output file"),
+		flagSet.BoolVar(&silent, "silent", false, "silent mode"),
+	)
+
+	usage := flagSet.UsageString()
+	require.Contains(t, usage, "Usage string example")
+	require.Contains(t, usage, "INPUT:\n   -t, -target string  target to scan")
+	require.Contains(t, usage, "OUTPUT:\n   -o, -output string  output file\n   -silent")
+	require.Contains(t, usage, "EXAMPLES:\n  tool -t example.com")
+
+	printed := &bytes.Buffer{}
+	flagSet.CommandLine.SetOutput(printed)
+	os.Args = []string{
+		os.Args[0],
+		"-h",
+	}
+	flagSet.usageFunc()
+	require.Equal(t, printed.String(), usage, "usage string should match the printed usage")
+	tearDown(t.Name())
+}
+
+func TestUsageStringGroups(t *testing.T) {
+	flagSet := NewFlagSet()
+	flagSet.SetDescription("Grouped usage example")
+	flagSet.SetOutput(&bytes.Buffer{})
+
+	var target, output string
+	var silent bool
+	flagSet.SetGroup("input", "Input")
+	flagSet.StringVarP(&target, "target", "t", "", "target to scan").Group("input")
+	flagSet.SetGroup("output", "Output")
+	flagSet.StringVarP(&output, "output", "o", "", "output file").Group("output")
+	flagSet.BoolVar(&silent, "silent", false, "silent mode").Group("output")
+
+	expected := "Grouped usage example\n\n" +
+		"Usage:\n  " + flagSet.CommandLine.Name() + " [flags]\n\n" +
+		"Flags:\n" +
+		"INPUT:\n" +
+		"   -t, -target string  target to scan\n" +
+		"\n" +
+		"OUTPUT:\n" +
+		"   -o, -output string  output file\n" +
+		"   -silent             silent mode\n" +
+		"\n"
+	require.Equal(t, expected, flagSet.UsageString())
+	tearDown(t.Name())
+}
+
+func TestGroups(t *testing.T) {
+	flagSet := NewFlagSet()
+
+	var stringData string
+	var intData int
+	var boolData bool
+
+	flagSet.SetGroup("String", "String")
+	flagSet.StringVar(&stringData, "string-value", "", "String example value example").Group("String")
+	flagSet.StringVarP(&stringData, "", "ts2", "test-string", "String with default value example #2").Group("String")
+	flagSet.StringVarP(&stringData, "string-with-default-value2", "ts", "test-string", "String with default value example #2").Group("String")
+
+	flagSet.CreateGroup("Integer", "Integer",
+		flagSet.IntVar(&intData, "int-value", 0, "Int value example"),
+		flagSet.IntVarP(&intData, "int-value2", "iv", 0, "Int value example #2"),
+	)
+
+	flagSet.SetGroup("Bool", "Boolean")
+	flagSet.BoolVarP(&boolData, "bool-value2", "bv", false, "Bool value example #2").Group("Bool")
+	flagSet.BoolVar(&boolData, "ungrouped", false, "Ungrouped bool value example")
+
+	expected := []FlagGroup{
+		{Name: "String", Description: "String", Flags: []string{"string-value", "ts2", "string-with-default-value2"}},
+		{Name: "Integer", Description: "Integer", Flags: []string{"int-value", "int-value2"}},
+		{Name: "Bool", Description: "Boolean", Flags: []string{"bool-value2"}},
+		{Name: "other options", Description: "other options", Flags: []string{"ungrouped"}},
+	}
+	require.Equal(t, expected, flagSet.Groups())
+
+	group, ok := flagSet.GroupOf("iv")
+	require.True(t, ok)
+	require.Equal(t, "Integer", group)
+
+	group, ok = flagSet.GroupOf("ts2")
+	require.True(t, ok)
+	require.Equal(t, "String", group)
+
+	_, ok = flagSet.GroupOf("ungrouped")
+	require.False(t, ok, "ungrouped flag should not have a group")
+
+	_, ok = flagSet.GroupOf("missing")
+	require.False(t, ok, "missing flag should not have a group")
+	tearDown(t.Name())
+}
+
 func TestIncorrectStringFlagsCausePanic(t *testing.T) {
 	flagSet := NewFlagSet()
 	var stringData string
@@ -225,6 +993,44 @@ func TestIncorrectFlagsCausePanic(t *testing.T) {
 	}
 }
 
+func TestValidateFlagNames(t *testing.T) {
+	tests := []struct {
+		short, long string
+		expected    string
+	}{
+		{"", "", `invalid flag with usage "String value example": short and long names cannot both be empty`},
+		{"", " string-value", `invalid flag name " string-value": name cannot have leading or trailing whitespace`},
+		{"sv\t", "string-value", `invalid flag name "sv\t": name cannot have leading or trailing whitespace`},
+		{"", "string value", `invalid flag name "string value": character ' ' is not allowed, use letters, digits, '-', '_' or '.'`},
+		{"s/v", "string-value", `invalid flag name "s/v": character '/' is not allowed, use letters, digits, '-', '_' or '.'`},
+		{"", "-string-value", `invalid flag name "-string-value": name cannot start with '-'`},
+		{"s=v", "string-value", `invalid flag name "s=v": character '=' is not allowed, use letters, digits, '-', '_' or '.'`},
+	}
+	for index, test := range tests {
+		uniqueName := strconv.Itoa(index)
+		t.Run(uniqueName, func(t *testing.T) {
+			flagSet := NewFlagSet()
+			flagSet.SetValidateFlagNames(true)
+			var stringData string
+			assert.PanicsWithError(t, test.expected, func() {
+				flagSet.StringVarP(&stringData, test.long, test.short, "", "String value example")
+			})
+			tearDown(uniqueName)
+		})
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		flagSet := NewFlagSet()
+		flagSet.SetValidateFlagNames(true)
+		var stringData string
+		assert.NotPanics(t, func() {
+			flagSet.StringVarP(&stringData, "string.value_2", "sv", "", "String value example")
+			flagSet.StringVarP(&stringData, "", "ts2", "", "String value example #2")
+		})
+		tearDown(t.Name())
+	})
+}
+
 type testSliceValue []interface{}
 
 func (value testSliceValue) String() string   { return "" }
@@ -267,6 +1073,34 @@ func TestParseStringSlice(t *testing.T) {
 
 }
 
+func TestParseStringSliceTrimQuotes(t *testing.T) {
+	header1 := "\"header1:value1\""
+	header2 := "'header2:\"value2\"'"
+	header3 := "\"header3\":\"value3\""
+	args := []string{"-H", header1, "-H", header2, "-H", header3}
+
+	options := StringSliceOptions
+	options.TrimQuotes = true
+	flagSet := NewFlagSet()
+	flagSet.DisableConfigLoading(true)
+	var stringSlice StringSlice
+	flagSet.StringSliceVarP(&stringSlice, "header", "H", nil, "Header values", options)
+	err := flagSet.ParseArgs(args)
+	require.Nil(t, err)
+	require.Equal(t, StringSlice{"header1:value1", "header2:\"value2\"", "header3\":\"value3"}, stringSlice)
+	tearDown(t.Name())
+
+	// quotes are kept without the option
+	flagSet = NewFlagSet()
+	flagSet.DisableConfigLoading(true)
+	var verbatimSlice StringSlice
+	flagSet.StringSliceVarP(&verbatimSlice, "header", "H", nil, "Header values", StringSliceOptions)
+	err = flagSet.ParseArgs(args)
+	require.Nil(t, err)
+	require.Equal(t, StringSlice{header1, header2, header3}, verbatimSlice)
+	tearDown(t.Name())
+}
+
 func TestParseCommaSeparatedStringSlice(t *testing.T) {
 	flagSet := NewFlagSet()
 
@@ -320,37 +1154,218 @@ value3`
 	tearDown(t.Name())
 }
 
-func TestConfigOnlyDataTypes(t *testing.T) {
-	flagSet := NewFlagSet()
-	var data StringSlice
-
-	flagSet.StringSliceVarConfigOnly(&data, "config-only", []string{}, "String slice config only flag example")
-
-	require.Nil(t, flagSet.CommandLine.Lookup("config-only"), "config-only flag should not be registered")
+func TestParseStringSliceEqualsForm(t *testing.T) {
+	testFile := "test.txt"
+	err := os.WriteFile(testFile, []byte("value1\nvalue2"), permissionutil.ConfigFilePermission)
+	require.Nil(t, err, "could not write temporary values file")
+	defer os.Remove(testFile)
 
-	configFileData := `
-config-only:
- - test
- - test2
- `
-	err := os.WriteFile("test.yaml", []byte(configFileData), permissionutil.ConfigFilePermission)
-	require.Nil(t, err, "could not write temporary config")
-	defer os.Remove("test.yaml")
+	tests := []struct {
+		name     string
+		options  Options
+		values   []string
+		expected StringSlice
+	}{
+		{"default", StringSliceOptions, []string{`"header1":"value1, value2"`, "header2"}, StringSlice{`"header1":"value1, value2"`, "header2"}},
+		{"comma-separated", CommaSeparatedStringSliceOptions, []string{`"value1,value2",value3`, "value4"}, StringSlice{"value1,value2", "value3", "value4"}},
+		{"normalized", NormalizedStringSliceOptions, []string{`'Value1,Value2', VALUE3`}, StringSlice{"value1,value2", "value3"}},
+		{"file-comma-separated", FileCommaSeparatedStringSliceOptions, []string{testFile, `"value3,value4"`}, StringSlice{"value1", "value2", "value3,value4"}},
+		{"file", FileStringSliceOptions, []string{testFile, `value3,"value4, value5"`}, StringSlice{"value1", "value2", `value3,"value4, value5"`}},
+	}
 
-	err = flagSet.MergeConfigFile("test.yaml")
-	require.Nil(t, err, "could not merge temporary config")
+	parse := func(t *testing.T, options Options, args []string) StringSlice {
+		flagSet := NewFlagSet()
+		var stringSlice StringSlice
+		flagSet.StringSliceVarP(&stringSlice, "header", "H", nil, "Header values", options)
+		os.Args = append([]string{os.Args[0]}, args...)
+		err := flagSet.Parse()
+		require.Nil(t, err)
+		tearDown(t.Name())
+		return stringSlice
+	}
 
-	require.Equal(t, StringSlice{"test", "test2"}, data, "could not get correct string slice")
-	tearDown(t.Name())
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var spaceArgs, equalsArgs []string
+			for i, value := range test.values {
+				name := "-H"
+				if i%2 == 1 {
+					name = "-header"
+				}
+				spaceArgs = append(spaceArgs, name, value)
+				equalsArgs = append(equalsArgs, name+"="+value)
+			}
+			spaceResult := parse(t, test.options, spaceArgs)
+			equalsResult := parse(t, test.options, equalsArgs)
+
+			require.Equal(t, test.expected, spaceResult, "could not get correct values with space form")
+			require.Equal(t, spaceResult, equalsResult, "equals form differs from space form")
+		})
+	}
 }
 
-func TestSetDefaultStringSliceValue(t *testing.T) {
-	var data StringSlice
+func TestParseUniqueStringSlice(t *testing.T) {
 	flagSet := NewFlagSet()
-	flagSet.StringSliceVar(&data, "test", []string{"A,A,A"}, "Default value for a test flag example", CommaSeparatedStringSliceOptions)
-	err := flagSet.CommandLine.Parse([]string{"-test", "item1"})
-	require.Nil(t, err)
-	require.Equal(t, StringSlice{"item1"}, data, "could not get correct string slice")
+
+	uniqueOptions := FileCommaSeparatedStringSliceOptions
+	uniqueOptions.Unique = true
+
+	var uniqueStringSlice StringSlice
+	flagSet.StringSliceVarP(&uniqueStringSlice, "unique-value", "UV", nil, "Unique Values. Expected usage: -UV value1,value2 -UV path/to/file", uniqueOptions)
+
+	testFile := "test.txt"
+	err := os.WriteFile(testFile, []byte("value2\nvalue4\nvalue1"), permissionutil.ConfigFilePermission)
+	require.Nil(t, err, "could not write temporary values file")
+	defer os.Remove(testFile)
+
+	os.Args = []string{
+		os.Args[0],
+		"-UV", "value1,value2,value1",
+		"-UV", "value3",
+		"-UV", testFile,
+		"-UV", "value3,value5",
+	}
+
+	err = flagSet.Parse()
+	assert.Nil(t, err)
+
+	assert.Equal(t, StringSlice{"value1", "value2", "value3", "value4", "value5"}, uniqueStringSlice)
+	tearDown(t.Name())
+}
+
+func TestParseNormalizedStringSlice(t *testing.T) {
+	flagSet := NewFlagSet()
+
+	normalizeOptions := Options{
+		IsFromFile: isFromFile,
+		Normalize:  func(s string) string { return strings.ToLower(strings.TrimSpace(s)) },
+		Unique:     true,
+	}
+
+	var headers StringSlice
+	flagSet.StringSliceVarP(&headers, "header", "H", nil, "Header names. Expected usage: -H X-Header,Accept -H path/to/file", normalizeOptions)
+
+	testFile := "test.txt"
+	err := os.WriteFile(testFile, []byte("  Content-Type\nX-HEADER\nUser-Agent  "), permissionutil.ConfigFilePermission)
+	require.Nil(t, err, "could not write temporary values file")
+	defer os.Remove(testFile)
+
+	os.Args = []string{
+		os.Args[0],
+		"-H", "X-Header, Accept",
+		"-H", testFile,
+		"-H", "ACCEPT",
+	}
+
+	err = flagSet.Parse()
+	assert.Nil(t, err)
+
+	assert.Equal(t, StringSlice{"x-header", "accept", "content-type", "user-agent"}, headers)
+	tearDown(t.Name())
+}
+
+func TestClearStringSlice(t *testing.T) {
+	err := os.WriteFile("test.yaml", []byte("tags: [a, b]"), permissionutil.ConfigFilePermission)
+	require.Nil(t, err, "could not write temporary config")
+	defer os.Remove("test.yaml")
+
+	tests := []struct {
+		name     string
+		isClear  func(string) bool
+		args     []string
+		expected StringSlice
+	}{
+		{"config", ClearOnEmpty, nil, StringSlice{"a", "b"}},
+		{"empty-sentinel", ClearOnEmpty, []string{"-tags", ""}, StringSlice{}},
+		{"dash-sentinel", func(s string) bool { return s == "-" }, []string{"-t", "-"}, StringSlice{}},
+		{"clear-then-add", ClearOnEmpty, []string{"-t", "c", "-t", "", "-t", "d"}, StringSlice{"d"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			options := CommaSeparatedStringSliceOptions
+			options.IsClear = test.isClear
+
+			flagSet := NewFlagSet()
+			flagSet.SetConfigFilePath("test.yaml")
+			var tags StringSlice
+			flagSet.StringSliceVarP(&tags, "tags", "t", nil, "Tags example", options)
+
+			err := flagSet.ParseArgs(test.args)
+			require.Nil(t, err)
+			require.Equal(t, test.expected, tags)
+			tearDown(t.Name())
+		})
+	}
+}
+
+func TestStringSliceMergeMode(t *testing.T) {
+	err := os.WriteFile("test.yaml", []byte("tags: [a, b]"), permissionutil.ConfigFilePermission)
+	require.Nil(t, err, "could not write temporary config")
+	defer os.Remove("test.yaml")
+
+	tests := []struct {
+		name      string
+		mergeMode MergeMode
+		unique    bool
+		args      []string
+		expected  StringSlice
+	}{
+		{"replace", MergeReplace, false, []string{"-tags", "b,c"}, StringSlice{"b", "c"}},
+		{"replace-config-only", MergeReplace, false, nil, StringSlice{"a", "b"}},
+		{"append", MergeAppend, false, []string{"-tags", "b,c"}, StringSlice{"a", "b", "b", "c"}},
+		{"append-unique", MergeAppend, true, []string{"-t", "b", "-t", "c"}, StringSlice{"a", "b", "c"}},
+		{"append-config-only", MergeAppend, false, nil, StringSlice{"a", "b"}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			options := CommaSeparatedStringSliceOptions
+			options.MergeMode = test.mergeMode
+			options.Unique = test.unique
+
+			flagSet := NewFlagSet()
+			flagSet.SetConfigFilePath("test.yaml")
+			var tags StringSlice
+			flagSet.StringSliceVarP(&tags, "tags", "t", []string{"default"}, "Tags example", options)
+
+			err := flagSet.ParseArgs(test.args)
+			require.Nil(t, err)
+			require.Equal(t, test.expected, tags)
+			tearDown(t.Name())
+		})
+	}
+}
+
+func TestConfigOnlyDataTypes(t *testing.T) {
+	flagSet := NewFlagSet()
+	var data StringSlice
+
+	flagSet.StringSliceVarConfigOnly(&data, "config-only", []string{}, "String slice config only flag example")
+
+	require.Nil(t, flagSet.CommandLine.Lookup("config-only"), "config-only flag should not be registered")
+
+	configFileData := `
+config-only:
+ - test
+ - test2
+ `
+	err := os.WriteFile("test.yaml", []byte(configFileData), permissionutil.ConfigFilePermission)
+	require.Nil(t, err, "could not write temporary config")
+	defer os.Remove("test.yaml")
+
+	err = flagSet.MergeConfigFile("test.yaml")
+	require.Nil(t, err, "could not merge temporary config")
+
+	require.Equal(t, StringSlice{"test", "test2"}, data, "could not get correct string slice")
+	tearDown(t.Name())
+}
+
+func TestSetDefaultStringSliceValue(t *testing.T) {
+	var data StringSlice
+	flagSet := NewFlagSet()
+	flagSet.StringSliceVar(&data, "test", []string{"A,A,A"}, "Default value for a test flag example", CommaSeparatedStringSliceOptions)
+	err := flagSet.CommandLine.Parse([]string{"-test", "item1"})
+	require.Nil(t, err)
+	require.Equal(t, StringSlice{"item1"}, data, "could not get correct string slice")
 
 	var data2 StringSlice
 	flagSet2 := NewFlagSet()
@@ -414,6 +1429,1238 @@ func TestCaseSensitiveFlagSet(t *testing.T) {
 	assert.Equal(t, expected, actual)
 }
 
+func TestSetFlagValue(t *testing.T) {
+	flagSet := NewFlagSet()
+	var stringData string
+	var intData int
+	var sliceData StringSlice
+	flagSet.StringVarP(&stringData, "string-value", "sv", "", "String value example")
+	flagSet.IntVar(&intData, "int-value", 0, "Int value example")
+	flagSet.StringSliceVar(&sliceData, "slice-value", []string{"a"}, "String slice value example", CommaSeparatedStringSliceOptions)
+
+	require.Nil(t, flagSet.Set("sv", "test"))
+	require.Nil(t, flagSet.Set("int-value", "42"))
+	require.Nil(t, flagSet.Set("slice-value", "b,c"))
+
+	require.Equal(t, "test", stringData, "could not set string value")
+	require.Equal(t, 42, intData, "could not set int value")
+	require.Equal(t, StringSlice{"b", "c"}, sliceData, "could not set string slice value")
+	require.NotNil(t, flagSet.Set("int-value", "not-a-number"), "could set invalid int value")
+	require.NotNil(t, flagSet.Set("unknown", "test"), "could set unknown flag")
+
+	var setFlags []string
+	flagSet.CommandLine.Visit(func(fl *flag.Flag) {
+		setFlags = append(setFlags, fl.Name)
+	})
+	require.ElementsMatch(t, []string{"string-value", "int-value", "slice-value"}, setFlags, "could not mark flags as set")
+	tearDown(t.Name())
+}
+
+func TestSetFlagValueValidation(t *testing.T) {
+	flagSet := NewFlagSet()
+	var threads int
+	var timeout time.Duration
+	var severity string
+	var headers StringSlice
+	flagSet.IntVar(&threads, "threads", 10, "number of threads").MinInt(1).MaxInt(100)
+	flagSet.DurationVar(&timeout, "timeout", time.Second, "timeout").MaxDuration(time.Minute)
+	flagSet.EnumVar(&severity, "severity", Type1, "severity", AllowdTypes{"low": Type1, "high": Type2})
+	options := StringSliceOptions
+	options.MaxItems = 2
+	options.ElementValidator = func(value string) error {
+		if !strings.Contains(value, ":") {
+			return errors.New("missing colon")
+		}
+		return nil
+	}
+	flagSet.StringSliceVar(&headers, "header", nil, "headers", options)
+
+	requireInvalid := func(name, value, expected string) {
+		err := flagSet.Set(name, value)
+		require.EqualError(t, err, expected)
+		var parseErr *ParseError
+		require.True(t, errors.As(err, &parseErr))
+		require.Equal(t, CategoryInvalid, parseErr.Category)
+	}
+	requireInvalid("threads", "500", "flag -threads must be between 1 and 100: got 500")
+	require.Equal(t, 10, threads, "invalid value should not be kept")
+	requireInvalid("timeout", "2m", "flag -timeout must be at most 1m0s: got 2m0s")
+	require.Equal(t, time.Second, timeout, "invalid value should not be kept")
+	err := flagSet.Set("severity", "medium")
+	require.ErrorContains(t, err, `invalid value "medium" for flag -severity: allowed values are `)
+	var parseErr *ParseError
+	require.True(t, errors.As(err, &parseErr))
+	require.Equal(t, CategoryInvalid, parseErr.Category)
+
+	require.Nil(t, flagSet.Set("header", "a:1"))
+	requireInvalid("header", "b", `flag -header has invalid value "b": missing colon`)
+	require.Equal(t, StringSlice{"a:1"}, headers, "invalid value should not be kept")
+	require.Nil(t, flagSet.Set("header", "b:2"))
+	requireInvalid("header", "c:3", "flag -header accepts at most 2 values: got 3")
+	require.Equal(t, StringSlice{"a:1", "b:2"}, headers)
+
+	require.Nil(t, flagSet.Set("threads", "50"))
+	require.Equal(t, 50, threads)
+	tearDown(t.Name())
+}
+
+func TestSetFlagValueRejectedNotProvided(t *testing.T) {
+	flagSet := NewFlagSet()
+	flagSet.DisableConfigLoading(true)
+	var threads int
+	var headers StringSlice
+	flagSet.IntVar(&threads, "threads", 10, "number of threads").MinInt(1).MaxInt(10).Required()
+	options := CommaSeparatedStringSliceOptions
+	options.MaxItems = 1
+	flagSet.StringSliceVar(&headers, "header", nil, "headers", options)
+
+	require.NotNil(t, flagSet.Set("threads", "500"))
+	require.NotNil(t, flagSet.Set("header", "a,b"))
+	require.Equal(t, 10, threads)
+	require.Empty(t, headers)
+	require.False(t, flagSet.Changed("threads"), "rejected value should not mark the flag as set")
+	require.False(t, flagSet.Changed("header"), "rejected value should not mark the flag as set")
+	require.Empty(t, flagSet.ProvidedFlags())
+
+	err := flagSet.ParseArgs(nil)
+	require.EqualError(t, err, "flag -threads is required")
+
+	require.Nil(t, flagSet.Set("threads", "5"))
+	require.True(t, flagSet.Changed("threads"))
+	require.Equal(t, 5, threads)
+	tearDown(t.Name())
+}
+
+func TestProvidedFlags(t *testing.T) {
+	flagSet := NewFlagSet()
+	var stringData, stringData2 string
+	var intData, intData2 int
+	var boolData bool
+	flagSet.StringVarP(&stringData, "string-value", "sv", "", "String value example")
+	flagSet.StringVar(&stringData2, "string-value2", "", "String value example #2")
+	flagSet.IntVarP(&intData, "int-value", "iv", 0, "Int value example")
+	flagSet.IntVar(&intData2, "int-value2", 10, "Int value example #2")
+	flagSet.BoolVar(&boolData, "bool-value", false, "Bool value example")
+
+	err := flagSet.CommandLine.Parse([]string{"-sv", "test", "-bool-value"})
+	require.Nil(t, err)
+
+	configFileData := `
+int-value: 543
+string-value: config`
+	err = os.WriteFile("test.yaml", []byte(configFileData), permissionutil.ConfigFilePermission)
+	require.Nil(t, err, "could not write temporary config")
+	defer os.Remove("test.yaml")
+
+	err = flagSet.MergeConfigFile("test.yaml")
+	require.Nil(t, err, "could not merge temporary config")
+
+	require.Equal(t, []string{"string-value", "int-value", "bool-value"}, flagSet.ProvidedFlags(), "could not get provided flags")
+	tearDown(t.Name())
+}
+
+func TestChanged(t *testing.T) {
+	flagSet := NewFlagSet()
+	flagSet.DisableConfigLoading(true)
+	var stringData, stringData2 string
+	var intData int
+	flagSet.StringVarP(&stringData, "string-value", "sv", "", "String value example").Alias("str")
+	flagSet.StringVar(&stringData2, "string-value2", "default", "String value example #2")
+	flagSet.IntVarP(&intData, "int-value", "iv", 0, "Int value example")
+
+	err := flagSet.MergeConfigReader(strings.NewReader("int-value: 543"), "yaml")
+	require.Nil(t, err, "could not merge config")
+	err = flagSet.ParseArgs([]string{"-str", "test"})
+	require.Nil(t, err)
+
+	for _, name := range []string{"string-value", "sv", "str", "int-value", "iv"} {
+		require.True(t, flagSet.Changed(name), "flag %v should be changed", name)
+	}
+	require.False(t, flagSet.Changed("string-value2"), "default-only flag should not be changed")
+	require.False(t, flagSet.Changed("missing"))
+	tearDown(t.Name())
+}
+
+func TestMarshalChangedConfig(t *testing.T) {
+	flagSet := NewFlagSet()
+	flagSet.DisableConfigLoading(true)
+	var target, output string
+	var threads int
+	var headers StringSlice
+	flagSet.StringVar(&target, "target", "", "target")
+	flagSet.IntVarP(&threads, "threads", "c", 10, "threads")
+	flagSet.StringVar(&output, "output", "out.txt", "output file")
+	flagSet.StringSliceVar(&headers, "header", nil, "headers", CommaSeparatedStringSliceOptions)
+
+	err := flagSet.ParseArgs([]string{"-c", "25", "-header", "a:b,c:d"})
+	require.Nil(t, err)
+
+	buffer := &bytes.Buffer{}
+	err = flagSet.MarshalChangedConfig(buffer)
+	require.Nil(t, err, "could not marshal changed config")
+	require.Equal(t, "threads: 25\nheader:\n  - a:b\n  - c:d\n", buffer.String())
+	tearDown(t.Name())
+}
+
+func TestExperimentalFlag(t *testing.T) {
+	createFlagSet := func() (*FlagSet, *string) {
+		flagSet := NewFlagSet()
+		var stringData, experimentalData string
+		flagSet.StringVar(&stringData, "string-value", "", "String value example")
+		flagSet.StringVarP(&experimentalData, "experimental-value", "ev", "", "Experimental value example").Experimental()
+		return flagSet, &experimentalData
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		flagSet, _ := createFlagSet()
+		os.Args = []string{
+			os.Args[0],
+			"-ev", "test",
+		}
+		err := flagSet.Parse()
+		require.NotNil(t, err, "could use experimental flag without experimental mode")
+		require.ErrorContains(t, err, "-experimental-value is experimental")
+		require.NotNil(t, flagSet.Set("experimental-value", "test"), "could set experimental flag without experimental mode")
+
+		output := &bytes.Buffer{}
+		flagSet.CommandLine.SetOutput(output)
+		os.Args = []string{
+			os.Args[0],
+			"-h",
+		}
+		flagSet.usageFunc()
+		require.Contains(t, output.String(), "-string-value")
+		require.NotContains(t, output.String(), "-experimental-value", "experimental flag was displayed")
+		tearDown(t.Name())
+	})
+
+	t.Run("enabled", func(t *testing.T) {
+		flagSet, experimentalData := createFlagSet()
+		flagSet.SetAllowExperimental(true)
+		os.Args = []string{
+			os.Args[0],
+			"-ev", "test",
+		}
+		err := flagSet.Parse()
+		require.Nil(t, err)
+		require.Equal(t, "test", *experimentalData, "could not get experimental value")
+		tearDown(t.Name())
+	})
+}
+
+func TestPrintResolution(t *testing.T) {
+	flagSet := NewFlagSet()
+	var stringData, stringData2 string
+	var intData int
+	flagSet.StringVarP(&stringData, "string-value", "sv", "", "String value example")
+	flagSet.StringVar(&stringData2, "string-value2", "default", "String value example #2")
+	flagSet.IntVar(&intData, "int-value", 0, "Int value example")
+
+	err := flagSet.CommandLine.Parse([]string{"-sv", "test"})
+	require.Nil(t, err)
+
+	err = os.WriteFile("test.yaml", []byte("int-value: 543"), permissionutil.ConfigFilePermission)
+	require.Nil(t, err, "could not write temporary config")
+	defer os.Remove("test.yaml")
+
+	err = flagSet.MergeConfigFile("test.yaml")
+	require.Nil(t, err, "could not merge temporary config")
+
+	output := &bytes.Buffer{}
+	err = flagSet.PrintResolution(output)
+	require.Nil(t, err)
+
+	expected := `FLAG            VALUE    SOURCE
+-string-value   test     cli
+-string-value2  default  default
+-int-value      543      config
+`
+	require.Equal(t, expected, output.String(), "could not get correct resolution table")
+	tearDown(t.Name())
+}
+
+func TestEqualLengthFlags(t *testing.T) {
+	createFlagSet := func() *FlagSet {
+		flagSet := NewFlagSet()
+		var names, values StringSlice
+		flagSet.StringSliceVarP(&names, "name", "n", nil, "Names of the values", CommaSeparatedStringSliceOptions)
+		flagSet.StringSliceVarP(&values, "value", "v", nil, "Values of the names", CommaSeparatedStringSliceOptions)
+		flagSet.SetEqualLength("name", "value")
+		return flagSet
+	}
+
+	t.Run("matching", func(t *testing.T) {
+		flagSet := createFlagSet()
+		os.Args = []string{
+			os.Args[0],
+			"-n", "a,b",
+			"-v", "1",
+			"-v", "2",
+		}
+		err := flagSet.Parse()
+		require.Nil(t, err)
+		tearDown(t.Name())
+	})
+
+	t.Run("mismatched", func(t *testing.T) {
+		flagSet := createFlagSet()
+		os.Args = []string{
+			os.Args[0],
+			"-n", "a,b",
+			"-v", "1,2,3",
+		}
+		err := flagSet.Parse()
+		require.NotNil(t, err)
+		require.EqualError(t, err, "flags -name and -value must have the same number of values: got 2 and 3")
+		tearDown(t.Name())
+	})
+}
+
+func TestCommandLineOverridesConfigScalars(t *testing.T) {
+	configData := []byte("output: config.txt\nthreads: 50\nsilent: true\ntimeout: 30s\nretries: 3")
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	require.Nil(t, os.WriteFile(configFile, configData, permissionutil.ConfigFilePermission))
+
+	type options struct {
+		output  string
+		threads int
+		silent  bool
+		timeout time.Duration
+		retries int
+	}
+	newFlagSet := func(opts *options) *FlagSet {
+		flagSet := NewFlagSet()
+		flagSet.SetConfigFilePath(configFile)
+		flagSet.StringVarP(&opts.output, "output", "o", "", "output file")
+		flagSet.IntVarP(&opts.threads, "threads", "t", 10, "number of threads")
+		flagSet.BoolVar(&opts.silent, "silent", false, "silent mode")
+		flagSet.DurationVar(&opts.timeout, "timeout", 10*time.Second, "timeout")
+		flagSet.IntVar(&opts.retries, "retries", 1, "number of retries")
+		return flagSet
+	}
+	// the bool and int flags are given their default value on the command line
+	args := []string{"-o", "cli.txt", "-t", "10", "-silent=false", "-timeout", "5s"}
+	expected := options{output: "cli.txt", threads: 10, silent: false, timeout: 5 * time.Second, retries: 3}
+
+	t.Run("default config", func(t *testing.T) {
+		var opts options
+		err := newFlagSet(&opts).ParseArgs(args)
+		require.Nil(t, err)
+		require.Equal(t, expected, opts)
+		tearDown(t.Name())
+	})
+
+	t.Run("config merged before parse", func(t *testing.T) {
+		var opts options
+		flagSet := newFlagSet(&opts)
+		flagSet.DisableConfigLoading(true)
+		require.Nil(t, flagSet.MergeConfigReader(bytes.NewReader(configData), "yaml"))
+		require.Nil(t, flagSet.ParseArgs(args))
+		require.Equal(t, expected, opts)
+		tearDown(t.Name())
+	})
+
+	t.Run("config merged after parse", func(t *testing.T) {
+		var opts options
+		flagSet := newFlagSet(&opts)
+		flagSet.DisableConfigLoading(true)
+		require.Nil(t, flagSet.ParseArgs(args))
+		require.Nil(t, flagSet.MergeConfigFile(configFile))
+		require.Equal(t, expected, opts)
+		tearDown(t.Name())
+	})
+}
+
+func TestStringSliceMaxItems(t *testing.T) {
+	dir := t.TempDir()
+	configFile := filepath.Join(dir, "config.yaml")
+	require.Nil(t, os.WriteFile(configFile, []byte("header:\n - a:1\n - b:2"), permissionutil.ConfigFilePermission))
+	valuesFile := filepath.Join(dir, "headers.txt")
+	require.Nil(t, os.WriteFile(valuesFile, []byte("c:3\nd:4"), permissionutil.ConfigFilePermission))
+
+	parse := func(maxItems int) (StringSlice, error) {
+		flagSet := NewFlagSet()
+		flagSet.SetConfigFilePath(configFile)
+		options := FileStringSliceOptions
+		options.MergeMode = MergeAppend
+		options.MaxItems = maxItems
+		var headers StringSlice
+		flagSet.StringSliceVarP(&headers, "header", "H", nil, "Header example", options)
+		err := flagSet.ParseArgs([]string{"-H", valuesFile})
+		tearDown(t.Name())
+		return headers, err
+	}
+
+	headers, err := parse(4)
+	require.Nil(t, err)
+	require.Equal(t, StringSlice{"a:1", "b:2", "c:3", "d:4"}, headers)
+
+	_, err = parse(3)
+	require.EqualError(t, err, "flag -header accepts at most 3 values: got 4")
+}
+
+func TestStringSliceElementValidator(t *testing.T) {
+	valuesFile := filepath.Join(t.TempDir(), "headers.txt")
+	require.Nil(t, os.WriteFile(valuesFile, []byte("X-File: 1\nmalformed-file"), permissionutil.ConfigFilePermission))
+
+	headerValidator := func(value string) error {
+		if name, _, found := strings.Cut(value, ":"); !found || strings.TrimSpace(name) == "" {
+			return fmt.Errorf("expected Name: value form")
+		}
+		return nil
+	}
+	parse := func(args ...string) (StringSlice, error) {
+		flagSet := NewFlagSet()
+		flagSet.DisableConfigLoading(true)
+		options := FileCommaSeparatedStringSliceOptions
+		options.ElementValidator = headerValidator
+		var headers StringSlice
+		flagSet.StringSliceVarP(&headers, "header", "H", nil, "Header example", options)
+		err := flagSet.ParseArgs(args)
+		tearDown(t.Name())
+		return headers, err
+	}
+
+	headers, err := parse("-H", "X-A: 1", "-H", "X-B: 2,X-C: 3")
+	require.Nil(t, err)
+	require.Equal(t, StringSlice{"X-A: 1", "X-B: 2", "X-C: 3"}, headers)
+
+	for name, args := range map[string][]string{
+		"repeated":        {"-H", "X-A: 1", "-H", "malformed"},
+		"comma-separated": {"-H", "X-A: 1,malformed"},
+	} {
+		_, err = parse(args...)
+		require.EqualError(t, err, `flag -header has invalid value "malformed": expected Name: value form`, name)
+	}
+
+	_, err = parse("-H", valuesFile)
+	var parseErr *ParseError
+	require.True(t, errors.As(err, &parseErr), "could not extract parse error")
+	require.Equal(t, "header", parseErr.Flag)
+	require.Equal(t, "malformed-file", parseErr.Value)
+}
+
+func TestCloneFlagSet(t *testing.T) {
+	flagSet := NewFlagSet()
+	var stringData string
+	var intData int
+	flagSet.SetGroup("Input", "input options")
+	flagSet.StringVarP(&stringData, "string-value", "sv", "default", "String value example").Group("Input")
+	flagSet.IntVar(&intData, "int-value", 10, "Int value example")
+
+	clone := flagSet.Clone()
+	require.Equal(t, flagSet.groups, clone.groups, "could not clone groups")
+
+	os.Args = []string{
+		os.Args[0],
+		"-sv", "test",
+	}
+	err := clone.Parse()
+	require.Nil(t, err)
+
+	require.Equal(t, []string{"string-value"}, clone.ProvidedFlags(), "could not get clone provided flags")
+	require.Empty(t, flagSet.ProvidedFlags(), "clone parse mutated original provided flags")
+	// values are bound to the same variables
+	require.Equal(t, "test", stringData)
+
+	clonedFlag := clone.CommandLine.Lookup("sv")
+	require.NotNil(t, clonedFlag)
+	require.Equal(t, "default", clonedFlag.DefValue, "could not keep registered default")
+
+	clone.flagKeys.values["int-value"].Group("Input")
+	require.Empty(t, flagSet.flagKeys.values["int-value"].group, "clone group change mutated original")
+	tearDown(t.Name())
+}
+
+func TestParseArgs(t *testing.T) {
+	configFileData := `
+int-value: 543`
+	err := os.WriteFile("test.yaml", []byte(configFileData), permissionutil.ConfigFilePermission)
+	require.Nil(t, err, "could not write temporary config")
+	defer os.Remove("test.yaml")
+
+	t.Run("config-merge", func(t *testing.T) {
+		flagSet := NewFlagSet()
+		flagSet.SetConfigFilePath("test.yaml")
+		var stringData string
+		var intData int
+		flagSet.StringVarP(&stringData, "string-value", "sv", "", "String value example")
+		flagSet.IntVar(&intData, "int-value", 10, "Int value example")
+
+		err := flagSet.ParseArgs([]string{"-sv", "test"})
+		require.Nil(t, err)
+		require.Equal(t, "test", stringData)
+		require.Equal(t, 543, intData, "could not merge config")
+		require.Equal(t, []string{"string-value", "int-value"}, flagSet.ProvidedFlags())
+		tearDown(t.Name())
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		flagSet := NewFlagSet()
+		flagSet.SetConfigFilePath("test.yaml")
+		var names, values StringSlice
+		flagSet.StringSliceVarP(&names, "name", "n", nil, "Names of the values", CommaSeparatedStringSliceOptions)
+		flagSet.StringSliceVarP(&values, "value", "v", nil, "Values of the names", CommaSeparatedStringSliceOptions)
+		flagSet.SetEqualLength("name", "value")
+
+		err := flagSet.ParseArgs([]string{"-n", "a,b", "-v", "1"})
+		require.NotNil(t, err, "could not run validation")
+		tearDown(t.Name())
+	})
+}
+
+func TestDisableConfigLoading(t *testing.T) {
+	homeDir := t.TempDir()
+	t.Setenv("HOME", homeDir)
+
+	flagSet := NewFlagSet()
+	flagSet.DisableConfigLoading(true)
+	var stringData string
+	flagSet.StringVar(&stringData, "string-value", "", "String value example")
+
+	configFilePath, err := flagSet.GetConfigFilePath()
+	require.Nil(t, err)
+	require.True(t, strings.HasPrefix(configFilePath, homeDir), "config file is not in temporary home")
+
+	err = flagSet.ParseArgs([]string{"-string-value", "test"})
+	require.Nil(t, err)
+	require.Equal(t, "test", stringData)
+	require.NoFileExists(t, configFilePath, "config file was created with config loading disabled")
+	require.NoDirExists(t, filepath.Dir(configFilePath), "config dir was created with config loading disabled")
+	tearDown(t.Name())
+}
+
+func TestOnParsed(t *testing.T) {
+	configFileData := `
+int-value: 543`
+	err := os.WriteFile("test.yaml", []byte(configFileData), permissionutil.ConfigFilePermission)
+	require.Nil(t, err, "could not write temporary config")
+	defer os.Remove("test.yaml")
+
+	t.Run("merged-values", func(t *testing.T) {
+		flagSet := NewFlagSet()
+		flagSet.SetConfigFilePath("test.yaml")
+		var stringData string
+		var intData int
+		flagSet.StringVar(&stringData, "string-value", "", "String value example")
+		flagSet.IntVar(&intData, "int-value", 10, "Int value example")
+
+		var calls []string
+		flagSet.OnParsed(func() error {
+			calls = append(calls, fmt.Sprintf("%s:%d", stringData, intData))
+			return nil
+		})
+		flagSet.OnParsed(func() error {
+			calls = append(calls, "second")
+			return nil
+		})
+
+		err := flagSet.ParseArgs([]string{"-string-value", "test"})
+		require.Nil(t, err)
+		require.Equal(t, []string{"test:543", "second"}, calls)
+		tearDown(t.Name())
+	})
+
+	t.Run("error", func(t *testing.T) {
+		flagSet := NewFlagSet()
+		flagSet.SetConfigFilePath("test.yaml")
+		var intData int
+		flagSet.IntVar(&intData, "int-value", 10, "Int value example")
+
+		var secondCalled bool
+		flagSet.OnParsed(func() error {
+			return fmt.Errorf("int-value %d is too high", intData)
+		})
+		flagSet.OnParsed(func() error {
+			secondCalled = true
+			return nil
+		})
+
+		err := flagSet.ParseArgs(nil)
+		require.EqualError(t, err, "int-value 543 is too high")
+		require.False(t, secondCalled, "callback was run after an error")
+		tearDown(t.Name())
+	})
+}
+
+func TestSecretFlag(t *testing.T) {
+	t.Setenv("GOFLAGS_TEST_API_KEY", "env-api-key")
+
+	flagSet := NewFlagSet()
+	var apiKey string
+	var stringData string
+	flagSet.StringVarEnv(&apiKey, "api-key", "ak", "", "GOFLAGS_TEST_API_KEY", "API key example").Secret()
+	flagSet.StringVar(&stringData, "string-value", "test", "String value example")
+
+	defaultConfig := string(flagSet.generateDefaultConfig())
+	require.NotContains(t, defaultConfig, "env-api-key", "secret default was written to config")
+	require.Contains(t, defaultConfig, "#api-key: \n")
+	require.Contains(t, defaultConfig, "#string-value: test")
+
+	flagSet.Marshal = true
+	defaultConfig = string(flagSet.generateDefaultConfig())
+	require.NotContains(t, defaultConfig, "env-api-key", "secret default was marshalled to config")
+	flagSet.Marshal = false
+
+	configFileData := `
+api-key: config-api-key`
+	err := os.WriteFile("test.yaml", []byte(configFileData), permissionutil.ConfigFilePermission)
+	require.Nil(t, err, "could not write temporary config")
+	defer os.Remove("test.yaml")
+	flagSet.SetConfigFilePath("test.yaml")
+
+	output := &bytes.Buffer{}
+	flagSet.SetTraceWriter(output)
+	err = flagSet.ParseArgs([]string{"-ak", "cli-api-key"})
+	require.Nil(t, err)
+	require.Equal(t, "cli-api-key", apiKey, "could not parse secret flag")
+	require.NotContains(t, output.String(), "cli-api-key", "secret value was traced")
+
+	output.Reset()
+	err = flagSet.PrintResolution(output)
+	require.Nil(t, err)
+	require.Contains(t, output.String(), secretMask)
+	require.NotContains(t, output.String(), "cli-api-key", "secret value was printed")
+	tearDown(t.Name())
+}
+
+func TestIntVarBounds(t *testing.T) {
+	parse := func(t *testing.T, args ...string) (int, error) {
+		var threads int
+		flagSet := NewFlagSet()
+		flagSet.SetConfigFilePath("test.yaml")
+		flagSet.IntVarP(&threads, "threads", "t", 10, "number of threads").MinInt(1).MaxInt(100)
+		err := flagSet.ParseArgs(args)
+		tearDown(t.Name())
+		return threads, err
+	}
+	err := os.WriteFile("test.yaml", []byte("threads: 500"), permissionutil.ConfigFilePermission)
+	require.Nil(t, err, "could not write temporary config")
+	defer os.Remove("test.yaml")
+
+	t.Run("lower-boundary", func(t *testing.T) {
+		threads, err := parse(t, "-t", "1")
+		require.Nil(t, err)
+		require.Equal(t, 1, threads)
+	})
+
+	t.Run("upper-boundary", func(t *testing.T) {
+		threads, err := parse(t, "-t", "100")
+		require.Nil(t, err)
+		require.Equal(t, 100, threads)
+	})
+
+	t.Run("below-min", func(t *testing.T) {
+		_, err := parse(t, "-t", "0")
+		require.EqualError(t, err, "flag -threads must be between 1 and 100: got 0")
+	})
+
+	t.Run("above-max-config", func(t *testing.T) {
+		_, err := parse(t)
+		require.EqualError(t, err, "flag -threads must be between 1 and 100: got 500")
+	})
+
+	t.Run("large-values", func(t *testing.T) {
+		// 2^53+1 and 2^53+2 are the same float64, compared as integers they differ
+		var id int
+		flagSet := NewFlagSet()
+		flagSet.DisableConfigLoading(true)
+		flagSet.IntVar(&id, "id", 0, "id").MaxInt(1<<53 + 1)
+		require.Nil(t, flagSet.ParseArgs([]string{"-id", "9007199254740993"}))
+		err := flagSet.ParseArgs([]string{"-id", "9007199254740994"})
+		require.EqualError(t, err, "flag -id must be at most 9007199254740993: got 9007199254740994")
+		tearDown(t.Name())
+	})
+
+	t.Run("not-int-flag", func(t *testing.T) {
+		var stringData string
+		flagSet := NewFlagSet()
+		flagSet.SetConfigFilePath("test.yaml")
+		flagSet.StringVar(&stringData, "string-value", "", "String value example").MinInt(1)
+		err := flagSet.ParseArgs(nil)
+		require.EqualError(t, err, "flag -string-value is not an int flag")
+		tearDown(t.Name())
+	})
+}
+
+func TestLookup(t *testing.T) {
+	flagSet := NewFlagSet()
+	var stringData, shortOnlyData string
+	flagSet.StringVarP(&stringData, "string-value", "sv", "test", "String value example")
+	flagSet.StringVarP(&shortOnlyData, "", "so", "", "Short only value example")
+
+	longFlag := flagSet.Lookup("string-value")
+	require.NotNil(t, longFlag)
+	require.Equal(t, "string-value", longFlag.Name)
+	require.Same(t, longFlag, flagSet.Lookup("sv"), "could not resolve short name to the same flag")
+
+	shortOnlyFlag := flagSet.Lookup("so")
+	require.NotNil(t, shortOnlyFlag)
+	require.Equal(t, "so", shortOnlyFlag.Name)
+
+	require.Nil(t, flagSet.Lookup("missing"))
+	tearDown(t.Name())
+}
+
+func TestDuplicateFlagRegistration(t *testing.T) {
+	t.Run("long-long", func(t *testing.T) {
+		flagSet := NewFlagSet()
+		var first, second string
+		flagSet.StringVarP(&first, "output", "o", "", "output file")
+		require.PanicsWithError(t, `flag name "output" of -out, -output is already registered by -o, -output`, func() {
+			flagSet.StringVarP(&second, "output", "out", "", "another output file")
+		})
+		tearDown(t.Name())
+	})
+
+	t.Run("short-long", func(t *testing.T) {
+		flagSet := NewFlagSet()
+		var first string
+		var second bool
+		flagSet.StringVarP(&first, "silent-output", "silent", "", "silent output file")
+		require.PanicsWithError(t, `flag name "silent" of -silent is already registered by -silent, -silent-output`, func() {
+			flagSet.BoolVar(&second, "silent", false, "silent mode")
+		})
+		tearDown(t.Name())
+	})
+}
+
+func TestRequiredFlag(t *testing.T) {
+	t.Run("missing", func(t *testing.T) {
+		flagSet := NewFlagSet()
+		var target, output string
+		flagSet.StringVarP(&target, "target", "t", "", "target to scan").Required()
+		flagSet.StringVarP(&output, "output", "o", "", "output file")
+
+		err := flagSet.ParseArgs([]string{"-o", "out.txt"})
+		require.EqualError(t, err, "flag -target is required")
+
+		var parseErr *ParseError
+		require.True(t, errors.As(err, &parseErr), "could not extract parse error")
+		require.Equal(t, "target", parseErr.Flag)
+		require.Equal(t, "", parseErr.Value)
+		require.Equal(t, CategoryRequired, parseErr.Category)
+		tearDown(t.Name())
+	})
+
+	t.Run("missing output", func(t *testing.T) {
+		flagSet := NewFlagSet()
+		flagSet.DisableConfigLoading(true)
+		var target, templates, output string
+		flagSet.StringVarP(&target, "target", "t", "", "target to scan").Required()
+		flagSet.StringVar(&templates, "templates", "", "templates to run").Required()
+		flagSet.StringVarP(&output, "output", "o", "", "output file").Required()
+		buffer := &bytes.Buffer{}
+		flagSet.SetOutput(buffer)
+
+		err := flagSet.ParseArgs([]string{"-o", "out.txt"})
+		require.EqualError(t, err, "flag -target is required")
+		require.Equal(t, "missing required flags:\n   -t, -target string  target to scan\n   -templates string   templates to run\n", buffer.String())
+
+		buffer.Reset()
+		require.Nil(t, flagSet.PrintMissingRequired(buffer))
+		require.Contains(t, buffer.String(), "-templates string")
+		require.NotContains(t, buffer.String(), "-output")
+		tearDown(t.Name())
+	})
+
+	t.Run("provided", func(t *testing.T) {
+		flagSet := NewFlagSet()
+		var target string
+		flagSet.StringVarP(&target, "target", "t", "", "target to scan").Required()
+
+		err := flagSet.ParseArgs([]string{"-t", "example.com"})
+		require.Nil(t, err)
+		require.Equal(t, "example.com", target)
+		tearDown(t.Name())
+	})
+
+	t.Run("invalid-bounds", func(t *testing.T) {
+		flagSet := NewFlagSet()
+		var threads int
+		flagSet.IntVar(&threads, "threads", 10, "number of threads").MinInt(1)
+
+		err := flagSet.ParseArgs([]string{"-threads", "0"})
+		var parseErr *ParseError
+		require.True(t, errors.As(err, &parseErr), "could not extract parse error")
+		require.Equal(t, ParseError{Flag: "threads", Value: "0", Category: CategoryInvalid, Err: parseErr.Err}, *parseErr)
+		tearDown(t.Name())
+	})
+}
+
+func TestSortFlags(t *testing.T) {
+	usageOf := func(sortFlags bool) (string, []FlagGroup) {
+		flagSet := NewFlagSet()
+		flagSet.SetSortFlags(sortFlags)
+		var target, exclude, list, outputFile string
+		flagSet.CreateGroup("Input", "Input",
+			flagSet.StringVarP(&target, "target", "u", "", "target to scan"),
+			flagSet.StringVarP(&exclude, "exclude", "e", "", "targets to exclude"),
+			flagSet.StringVarP(&list, "list", "l", "", "list of targets"),
+		)
+		flagSet.StringVarP(&outputFile, "output", "o", "", "output file")
+
+		output := &bytes.Buffer{}
+		flagSet.CommandLine.SetOutput(output)
+		flagSet.args = []string{"-h"}
+		flagSet.usageFunc()
+		groups := flagSet.Groups()
+		tearDown(t.Name())
+		return output.String(), groups
+	}
+	flagOrder := func(usage string) []string {
+		var names []string
+		for _, line := range strings.Split(usage, "\n") {
+			for _, name := range []string{"-target", "-exclude", "-list"} {
+				if strings.Contains(line, name+" ") {
+					names = append(names, name)
+				}
+			}
+		}
+		return names
+	}
+
+	unsortedUsage, unsortedGroups := usageOf(false)
+	require.Equal(t, []string{"-target", "-exclude", "-list"}, flagOrder(unsortedUsage))
+	require.Equal(t, []string{"target", "exclude", "list"}, unsortedGroups[0].Flags)
+
+	sortedUsage, sortedGroups := usageOf(true)
+	require.Equal(t, []string{"-exclude", "-list", "-target"}, flagOrder(sortedUsage))
+	require.Equal(t, []string{"exclude", "list", "target"}, sortedGroups[0].Flags)
+	require.Equal(t, "Input", sortedGroups[0].Name, "group order should be kept")
+	require.Equal(t, []string{"output"}, sortedGroups[1].Flags)
+}
+
+func TestDisableBuiltinHelp(t *testing.T) {
+	flagSet := NewFlagSet()
+	flagSet.DisableBuiltinHelp()
+	var output string
+	flagSet.StringVarP(&output, "output", "o", "", "output file")
+
+	// -h would print usage and exit the process if handled by the builtin help
+	err := flagSet.ParseArgs([]string{"-h", "-o", "out.txt", "--help"})
+	require.Nil(t, err)
+	require.True(t, flagSet.HelpRequested(), "help request was not reported")
+	require.Equal(t, "out.txt", output)
+	require.Empty(t, flagSet.CommandLine.Args())
+
+	err = flagSet.ParseArgs([]string{"-o", "out.txt", "--", "-h"})
+	require.Nil(t, err)
+	require.False(t, flagSet.HelpRequested(), "help after terminator should be kept as argument")
+	require.Equal(t, []string{"-h"}, flagSet.CommandLine.Args())
+	tearDown(t.Name())
+}
+
+func TestExitOnHelp(t *testing.T) {
+	flagSet := NewFlagSet()
+	flagSet.SetDescription("Exit on help example")
+	flagSet.SetExitOnHelp(false)
+	var target string
+	flagSet.StringVarP(&target, "target", "u", "", "target to scan").Required()
+
+	reader, writer, err := os.Pipe()
+	require.Nil(t, err, "could not create pipe")
+	stdout := os.Stdout
+	os.Stdout = writer
+	err = flagSet.ParseArgs([]string{"-h"})
+	os.Stdout = stdout
+	writer.Close()
+	output, _ := io.ReadAll(reader)
+
+	require.Nil(t, err, "parse should return normally after help")
+	require.True(t, flagSet.HelpRequested())
+	require.Contains(t, string(output), "Exit on help example")
+	require.Contains(t, string(output), "target to scan")
+	tearDown(t.Name())
+}
+
+func TestFlagAlias(t *testing.T) {
+	t.Run("command-line", func(t *testing.T) {
+		flagSet := NewFlagSet()
+		var output string
+		flagSet.StringVarP(&output, "output", "o", "", "output file").Alias("out", "o2")
+
+		err := flagSet.ParseArgs([]string{"-out", "out.txt"})
+		require.Nil(t, err)
+		require.Equal(t, "out.txt", output)
+		require.Same(t, flagSet.Lookup("output"), flagSet.Lookup("out"), "alias should resolve to the flag")
+
+		groups := flagSet.Groups()
+		require.Len(t, groups, 1)
+		require.Equal(t, []string{"output"}, groups[0].Flags, "alias should not create a usage row")
+		tearDown(t.Name())
+	})
+
+	t.Run("config-file", func(t *testing.T) {
+		flagSet := NewFlagSet()
+		var output string
+		flagSet.StringVarP(&output, "output", "o", "", "output file").Alias("out")
+
+		err := flagSet.ParseArgs(nil)
+		require.Nil(t, err)
+
+		err = os.WriteFile("test.yaml", []byte("output: config.txt"), permissionutil.ConfigFilePermission)
+		require.Nil(t, err, "could not write temporary config")
+		defer os.Remove("test.yaml")
+
+		err = flagSet.MergeConfigFile("test.yaml")
+		require.Nil(t, err, "could not merge temporary config")
+		require.Equal(t, "config.txt", output)
+		tearDown(t.Name())
+	})
+
+	t.Run("before parse", func(t *testing.T) {
+		flagSet := NewFlagSet()
+		var output string
+		flagSet.StringVarP(&output, "output", "o", "", "output file").Alias("out")
+
+		require.Nil(t, flagSet.Set("out", "set.txt"))
+		require.Equal(t, "set.txt", output)
+		require.Same(t, flagSet.Lookup("output"), flagSet.Lookup("out"), "alias should resolve to the flag")
+		require.True(t, flagSet.Changed("out"))
+		value, err := flagSet.GetString("out")
+		require.Nil(t, err)
+		require.Equal(t, "set.txt", value)
+		tearDown(t.Name())
+	})
+
+	t.Run("duplicate", func(t *testing.T) {
+		flagSet := NewFlagSet()
+		var output, outputFormat string
+		outputFlag := flagSet.StringVarP(&output, "output", "o", "", "output file")
+		flagSet.StringVar(&outputFormat, "out", "", "output format")
+
+		require.PanicsWithError(t, `alias "out" of -o, -output is already registered by -out`, func() {
+			outputFlag.Alias("out")
+		})
+		tearDown(t.Name())
+	})
+
+	t.Run("flag after alias", func(t *testing.T) {
+		flagSet := NewFlagSet()
+		var output, outputFormat string
+		flagSet.StringVarP(&output, "output", "o", "", "output file").Alias("out")
+
+		require.PanicsWithError(t, `flag name "out" of -out is already registered by -o, -output`, func() {
+			flagSet.StringVar(&outputFormat, "out", "", "output format")
+		})
+		tearDown(t.Name())
+	})
+}
+
+func TestMetaGroup(t *testing.T) {
+	flagSet := NewFlagSet()
+	var target, output string
+	flagSet.CallbackVar(func() {}, "version", "show version of the project")
+	flagSet.SetMetaGroup()
+	flagSet.CreateGroup("Input", "Input",
+		flagSet.StringVarP(&target, "target", "u", "", "target to scan"),
+	)
+	flagSet.StringVarP(&output, "output", "o", "", "output file")
+	flagSet.CallbackVarP(func() {}, "update", "up", "update tool to the latest version")
+	flagSet.CreateGroup("Output", "Output")
+
+	usage := &bytes.Buffer{}
+	flagSet.CommandLine.SetOutput(usage)
+	flagSet.args = []string{"-h"}
+	flagSet.usageFunc()
+
+	output = usage.String()
+	metaIndex := strings.Index(output, "META:")
+	require.NotEqual(t, -1, metaIndex, "meta group was not rendered")
+	for _, group := range []string{"INPUT:", "OUTPUT:", "OTHER OPTIONS:"} {
+		index := strings.Index(output, group)
+		require.NotEqual(t, -1, index, "group %v was not rendered", group)
+		require.Less(t, index, metaIndex, "meta group should be rendered after %v", group)
+	}
+	require.Greater(t, strings.Index(output, "-version"), metaIndex)
+	require.Greater(t, strings.Index(output, "-update"), metaIndex)
+	require.Less(t, strings.Index(output, "-output"), metaIndex)
+
+	groups := flagSet.Groups()
+	require.Equal(t, FlagGroup{Name: "meta", Description: "Meta", Flags: []string{"version", "update"}}, groups[len(groups)-1])
+	tearDown(t.Name())
+}
+
+func TestDoubleDashTerminator(t *testing.T) {
+	flagSet := NewFlagSet()
+	flagSet.DisableConfigLoading(true)
+	var target string
+	var verbose bool
+	flagSet.StringVarP(&target, "target", "t", "", "target")
+	flagSet.BoolVarP(&verbose, "verbose", "v", false, "verbose")
+
+	err := flagSet.ParseArgs([]string{"-t", "example.com", "--", "-verbose", "--target", "other", "arg"})
+	require.Nil(t, err)
+	require.Equal(t, "example.com", target)
+	require.False(t, verbose, "flags after the terminator should not be parsed")
+	require.Equal(t, []string{"-verbose", "--target", "other", "arg"}, flagSet.Args())
+	tearDown(t.Name())
+}
+
+func TestPositionalArgs(t *testing.T) {
+	flagSet := NewFlagSet()
+	flagSet.DisableConfigLoading(true)
+	var threads int
+	flagSet.IntVarP(&threads, "threads", "c", 10, "threads")
+
+	err := flagSet.ParseArgs([]string{"-c", "5", "input1.txt", "input2.txt"})
+	require.Nil(t, err)
+	require.Equal(t, 5, threads)
+	require.Equal(t, 2, flagSet.NArg())
+	require.Equal(t, []string{"input1.txt", "input2.txt"}, flagSet.Args())
+	tearDown(t.Name())
+}
+
+func TestSuggestFlags(t *testing.T) {
+	newFlagSet := func() *FlagSet {
+		flagSet := NewFlagSet()
+		flagSet.DisableConfigLoading(true)
+		flagSet.SetSuggestFlags(true)
+		var output, target string
+		var silent bool
+		flagSet.StringVarP(&output, "output", "o", "", "output file")
+		flagSet.StringVarP(&target, "target", "t", "", "target")
+		flagSet.BoolVar(&silent, "silent", false, "silent mode")
+		return flagSet
+	}
+
+	t.Run("suggestion", func(t *testing.T) {
+		err := newFlagSet().ParseArgs([]string{"-silent", "-t", "example.com", "--ouptut", "out.txt"})
+		require.EqualError(t, err, "flag provided but not defined: -ouptut (did you mean -output?)")
+		var parseErr *ParseError
+		require.True(t, errors.As(err, &parseErr))
+		require.Equal(t, CategoryUnknown, parseErr.Category)
+		require.Equal(t, "ouptut", parseErr.Flag)
+		tearDown(t.Name())
+	})
+
+	t.Run("no suggestion", func(t *testing.T) {
+		err := newFlagSet().ParseArgs([]string{"-concurrency", "10"})
+		require.EqualError(t, err, "flag provided but not defined: -concurrency")
+		tearDown(t.Name())
+	})
+
+	t.Run("values and positionals", func(t *testing.T) {
+		err := newFlagSet().ParseArgs([]string{"-o", "-ouptut", "input.txt", "-unknown"})
+		require.Nil(t, err, "flag values and positional arguments should not be checked")
+		tearDown(t.Name())
+	})
+}
+
+func TestAllowAbbreviations(t *testing.T) {
+	var output, outputDir, org string
+	var silent bool
+	newFlagSet := func() *FlagSet {
+		flagSet := NewFlagSet()
+		flagSet.DisableConfigLoading(true)
+		flagSet.SetAllowAbbreviations(true)
+		flagSet.StringVarP(&output, "output", "o", "", "output file")
+		flagSet.StringVar(&outputDir, "outdir", "", "output directory")
+		flagSet.StringVar(&org, "org", "", "organization")
+		flagSet.BoolVar(&silent, "silent", false, "silent mode")
+		return flagSet
+	}
+
+	t.Run("unambiguous", func(t *testing.T) {
+		err := newFlagSet().ParseArgs([]string{"-outp", "out.txt", "--sil", "-or=acme", "input.txt"})
+		require.Nil(t, err)
+		require.Equal(t, "out.txt", output)
+		require.Equal(t, "acme", org)
+		require.True(t, silent)
+		tearDown(t.Name())
+	})
+
+	t.Run("exact match", func(t *testing.T) {
+		err := newFlagSet().ParseArgs([]string{"-org", "acme", "-o", "out.txt"})
+		require.Nil(t, err, "exact names should not be treated as prefixes")
+		require.Equal(t, "acme", org)
+		require.Equal(t, "out.txt", output)
+		tearDown(t.Name())
+	})
+
+	t.Run("ambiguous", func(t *testing.T) {
+		err := newFlagSet().ParseArgs([]string{"-out", "out.txt"})
+		require.EqualError(t, err, "ambiguous flag -out: could be -outdir, -output")
+		var parseErr *ParseError
+		require.True(t, errors.As(err, &parseErr))
+		require.Equal(t, CategoryUnknown, parseErr.Category)
+		tearDown(t.Name())
+	})
+}
+
+func TestSetOutput(t *testing.T) {
+	flagSet := NewFlagSet()
+	flagSet.DisableConfigLoading(true)
+	flagSet.SetExitOnHelp(false)
+	flagSet.SetDescription("Output example")
+	var output string
+	flagSet.StringVarP(&output, "output", "o", "", "output file").ConfigKey("output-file")
+
+	buffer := &bytes.Buffer{}
+	flagSet.SetOutput(buffer)
+
+	err := flagSet.MergeConfigReader(strings.NewReader("output: a.txt\noutput-file: b.txt"), "yaml")
+	require.Nil(t, err)
+	err = flagSet.ParseArgs([]string{"-h"})
+	require.Nil(t, err)
+
+	captured := buffer.String()
+	require.Contains(t, captured, `warning: config keys "output-file" and "output" are both set for flag -output`)
+	require.Contains(t, captured, "Output example")
+	require.Contains(t, captured, "-o, -output string")
+	tearDown(t.Name())
+}
+
+func TestRemove(t *testing.T) {
+	newFlagSet := func(buffer *bytes.Buffer) *FlagSet {
+		flagSet := NewFlagSet()
+		flagSet.DisableConfigLoading(true)
+		flagSet.SetExitOnHelp(false)
+		flagSet.SetSuggestFlags(true)
+		flagSet.SetOutput(buffer)
+		var output, target string
+		flagSet.CreateGroup("io", "Input-Output",
+			flagSet.StringVarP(&target, "target", "t", "", "target to scan"),
+			flagSet.StringVarP(&output, "output", "o", "", "output file").Alias("out"),
+		)
+		return flagSet
+	}
+
+	t.Run("removed", func(t *testing.T) {
+		buffer := &bytes.Buffer{}
+		flagSet := newFlagSet(buffer)
+		require.Nil(t, flagSet.Remove("o"))
+		require.Nil(t, flagSet.Lookup("output"))
+		require.Nil(t, flagSet.Lookup("out"))
+
+		for _, name := range []string{"-o", "-output", "-out"} {
+			err := flagSet.ParseArgs([]string{name, "a.txt"})
+			require.NotNil(t, err, "removed flag %s should not parse", name)
+		}
+		require.Nil(t, flagSet.ParseArgs([]string{"-t", "example.com"}))
+		target, err := flagSet.GetString("target")
+		require.Nil(t, err)
+		require.Equal(t, "example.com", target)
+
+		require.Nil(t, flagSet.ParseArgs([]string{"-h"}))
+		captured := buffer.String()
+		require.Contains(t, captured, "-t, -target string")
+		require.NotContains(t, captured, "output")
+	})
+
+	t.Run("absent", func(t *testing.T) {
+		flagSet := newFlagSet(&bytes.Buffer{})
+		require.EqualError(t, flagSet.Remove("missing"), "no such flag -missing")
+		require.Nil(t, flagSet.Remove("output"))
+		require.EqualError(t, flagSet.Remove("output"), "no such flag -output")
+	})
+	tearDown(t.Name())
+}
+
+func TestFlagFromFile(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "key")
+	require.Nil(t, os.WriteFile(keyFile, []byte("  s3cr3t-key\n"), os.ModePerm))
+
+	newFlagSet := func(apiKey *string) *FlagSet {
+		flagSet := NewFlagSet()
+		flagSet.DisableConfigLoading(true)
+		flagSet.StringVar(apiKey, "api-key-file", "", "api key file").FromFile().Secret()
+		return flagSet
+	}
+
+	var apiKey string
+	flagSet := newFlagSet(&apiKey)
+	err := flagSet.ParseArgs([]string{"-api-key-file", keyFile})
+	require.Nil(t, err)
+	require.Equal(t, "s3cr3t-key", apiKey, "could not read value from file")
+	require.NotContains(t, string(flagSet.generateDefaultConfig()), "s3cr3t-key")
+	tearDown(t.Name())
+
+	missingFile := filepath.Join(t.TempDir(), "missing")
+	err = newFlagSet(&apiKey).ParseArgs([]string{"-api-key-file", missingFile})
+	var parseErr *ParseError
+	require.True(t, errors.As(err, &parseErr), "missing file should return a parse error")
+	require.Equal(t, "api-key-file", parseErr.Flag)
+	require.Contains(t, err.Error(), "could not read value of flag -api-key-file from "+missingFile)
+	tearDown(t.Name())
+}
+
+func TestAddComputedDefault(t *testing.T) {
+	newFlagSet := func(rateLimit, rateLimitMinute *int) *FlagSet {
+		flagSet := NewFlagSet()
+		flagSet.DisableConfigLoading(true)
+		flagSet.IntVar(rateLimit, "rate-limit", 0, "rate limit per second").Required()
+		flagSet.IntVar(rateLimitMinute, "rate-limit-minute", 0, "rate limit per minute")
+		flagSet.AddComputedDefault(func(flagSet *FlagSet) error {
+			if flagSet.Changed("rate-limit") || !flagSet.Changed("rate-limit-minute") {
+				return nil
+			}
+			return flagSet.Set("rate-limit", strconv.Itoa(*rateLimitMinute/60))
+		})
+		return flagSet
+	}
+
+	var rateLimit, rateLimitMinute int
+	err := newFlagSet(&rateLimit, &rateLimitMinute).ParseArgs([]string{"-rate-limit-minute", "600"})
+	require.Nil(t, err, "computed value should be set before required checks")
+	require.Equal(t, 10, rateLimit)
+	tearDown(t.Name())
+
+	rateLimit, rateLimitMinute = 0, 0
+	err = newFlagSet(&rateLimit, &rateLimitMinute).ParseArgs(nil)
+	var parseErr *ParseError
+	require.True(t, errors.As(err, &parseErr))
+	require.Equal(t, CategoryRequired, parseErr.Category)
+	tearDown(t.Name())
+}
+
+func TestValidate(t *testing.T) {
+	require.Nil(t, NewFlagSet().Validate(), "empty flag set should be valid")
+
+	t.Run("collisions", func(t *testing.T) {
+		flagSet := NewFlagSet()
+		flagSet.SetDeferFlagCollisions(true)
+		var output, outputFile, outputDir, target, targets string
+		flagSet.StringVarP(&output, "output", "o", "", "output")
+		require.NotPanics(t, func() {
+			flagSet.StringVarP(&outputFile, "output-file", "o", "", "output file")
+			flagSet.StringVar(&outputFile, "output-path", "", "output path").Alias("output", "out")
+			flagSet.StringVar(&outputDir, "output-dir", "", "output directory").Alias("out")
+			flagSet.StringVarP(&target, "target", "t", "", "target")
+			flagSet.StringVar(&targets, "t", "", "targets")
+		})
+
+		err := flagSet.Validate()
+		require.NotNil(t, err, "collisions should be reported")
+		require.Equal(t, `flag name "o" of -o, -output-file is already registered by -o, -output
+alias "output" of -output-path is already registered by -o, -output
+alias "out" of -output-dir is already registered by -output-path
+flag name "t" of -t is already registered by -t, -target`, err.Error())
+
+		// the first registration of a name is kept
+		require.Nil(t, flagSet.ParseArgs([]string{"-o", "a", "-out", "b", "-t", "c"}))
+		require.Equal(t, "a", output)
+		require.Equal(t, "b", outputFile)
+		require.Empty(t, outputDir)
+		require.Equal(t, "c", target)
+		tearDown(t.Name())
+	})
+
+	t.Run("invalid names", func(t *testing.T) {
+		flagSet := NewFlagSet()
+		var output, target string
+		flagSet.StringVarP(&output, "output", "o", "", "output").Alias("out file", "out")
+		flagSet.StringVar(&target, "target url", "", "target")
+
+		err := flagSet.Validate()
+		require.NotNil(t, err, "invalid names should be reported")
+		require.Equal(t, `invalid flag name "out file": character ' ' is not allowed, use letters, digits, '-', '_' or '.'
+invalid flag name "target url": character ' ' is not allowed, use letters, digits, '-', '_' or '.'`, err.Error())
+		tearDown(t.Name())
+	})
+}
+
 func tearDown(uniqueValue string) {
 	flag.CommandLine = flag.NewFlagSet(uniqueValue, flag.ContinueOnError)
 	flag.CommandLine.Usage = flag.Usage
@@ -18,3 +18,17 @@ func (insertionOrderedMap *InsertionOrderedMap) Set(key string, value *FlagData)
 		insertionOrderedMap.keys = append(insertionOrderedMap.keys, key)
 	}
 }
+
+// delete removes a key keeping the insertion order of the others
+func (insertionOrderedMap *InsertionOrderedMap) delete(key string) {
+	if _, present := insertionOrderedMap.values[key]; !present {
+		return
+	}
+	delete(insertionOrderedMap.values, key)
+	for i, existing := range insertionOrderedMap.keys {
+		if existing == key {
+			insertionOrderedMap.keys = append(insertionOrderedMap.keys[:i], insertionOrderedMap.keys[i+1:]...)
+			break
+		}
+	}
+}
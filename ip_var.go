@@ -0,0 +1,63 @@
+package goflags
+
+import (
+	"fmt"
+	"net"
+)
+
+type ipValue net.IP
+
+func (i *ipValue) Set(s string) error {
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return fmt.Errorf("invalid ip address %q", s)
+	}
+	*i = ipValue(ip)
+	return nil
+}
+
+func (i *ipValue) String() string {
+	if len(*i) == 0 {
+		return ""
+	}
+	return net.IP(*i).String()
+}
+
+func (i *ipValue) Get() any { return net.IP(*i) }
+
+func (i *ipValue) displayType() string { return "string" }
+
+// IPVar adds an ip address flag with a longname
+func (flagSet *FlagSet) IPVar(field *net.IP, long string, defaultValue string, usage string) *FlagData {
+	return flagSet.IPVarP(field, long, "", defaultValue, usage)
+}
+
+// IPVarP adds an ip address flag with a shortname and longname.
+// Both IPv4 (ex: 192.168.1.1) and IPv6 (ex: 2001:db8::1) addresses are accepted.
+func (flagSet *FlagSet) IPVarP(field *net.IP, long, short string, defaultValue string, usage string) *FlagData {
+	if field == nil {
+		panic(fmt.Errorf("field cannot be nil for flag -%v", long))
+	}
+	value := (*ipValue)(field)
+	if defaultValue != "" {
+		if err := value.Set(defaultValue); err != nil {
+			panic(fmt.Errorf("failed to set default value for flag -%v: %v", long, err))
+		}
+	}
+	flagData := &FlagData{
+		usage:        usage,
+		long:         long,
+		defaultValue: defaultValue,
+	}
+	if !flagSet.checkDuplicateFlag(long, short) {
+		return flagData
+	}
+	if short != "" {
+		flagData.short = short
+		flagSet.CommandLine.Var(value, short, usage)
+		flagSet.setFlagKey(short, flagData)
+	}
+	flagSet.CommandLine.Var(value, long, usage)
+	flagSet.setFlagKey(long, flagData)
+	return flagData
+}
@@ -0,0 +1,39 @@
+package goflags
+
+import (
+	"net"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIPVar(t *testing.T) {
+	t.Run("valid-ip", func(t *testing.T) {
+		var ipv4, ipv6 net.IP
+		flagSet := NewFlagSet()
+		flagSet.CreateGroup("Input", "Input",
+			flagSet.IPVar(&ipv4, "ipv4", "", "ipv4 address"),
+			flagSet.IPVarP(&ipv6, "ipv6", "6", "::1", "ipv6 address"),
+		)
+		os.Args = []string{
+			os.Args[0],
+			"-ipv4", "192.168.1.1",
+			"-6", "2001:db8::1",
+		}
+		err := flagSet.Parse()
+		assert.Nil(t, err)
+		assert.Equal(t, "192.168.1.1", ipv4.String())
+		assert.Equal(t, "2001:db8::1", ipv6.String())
+		tearDown(t.Name())
+	})
+
+	t.Run("invalid-ip", func(t *testing.T) {
+		var ip net.IP
+		err := (*ipValue)(&ip).Set("192.168.1")
+		assert.NotNil(t, err)
+		assert.ErrorContains(t, err, "invalid ip address \"192.168.1\"")
+		assert.Nil(t, ip)
+		tearDown(t.Name())
+	})
+}
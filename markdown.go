@@ -0,0 +1,64 @@
+package goflags
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// MarkdownDoc writes the flags as markdown tables, one per group, with
+// the flag names, type, default value and description in usage order.
+func (flagSet *FlagSet) MarkdownDoc(w io.Writer) error {
+	groups := flagSet.Groups()
+	for i, group := range groups {
+		if i > 0 {
+			if _, err := fmt.Fprintln(w); err != nil {
+				return err
+			}
+		}
+		// flags without groups are written without heading like in usage
+		if len(flagSet.groups) > 0 {
+			if _, err := fmt.Fprintf(w, "## %s\n\n", escapeMarkdown(group.Description)); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprint(w, "| Flag | Type | Default | Description |\n|------|------|---------|-------------|\n"); err != nil {
+			return err
+		}
+		for _, name := range group.Flags {
+			data := flagSet.flagKeys.values[name]
+			currentFlag := flagSet.CommandLine.Lookup(name)
+			valueType := reflect.TypeOf(currentFlag.Value)
+
+			flagDisplayType, usage := usageTypeAndDescription(currentFlag, valueType)
+			if flagDisplayType == "" {
+				flagDisplayType = "bool"
+			}
+			if allowedValues := enumAllowedValues(currentFlag.Value); len(allowedValues) > 0 {
+				usage += " (allowed: " + strings.Join(allowedValues, ", ") + ")"
+			}
+			if data.since != "" {
+				usage += " (since " + data.since + ")"
+			}
+			defaultValue := usageDefaultValue(data, currentFlag, valueType)
+
+			row := fmt.Sprintf("| `%s` | %s | %s | %s |\n",
+				strings.TrimSpace(createUsageFlagNames(data)),
+				escapeMarkdown(flagDisplayType),
+				escapeMarkdown(defaultValue),
+				escapeMarkdown(usage),
+			)
+			if _, err := fmt.Fprint(w, row); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// escapeMarkdown escapes a value to be written in a markdown table cell
+func escapeMarkdown(value string) string {
+	value = strings.ReplaceAll(value, "|", "\\|")
+	return strings.ReplaceAll(value, "\n", "<br>")
+}
@@ -0,0 +1,40 @@
+package goflags
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarkdownDoc(t *testing.T) {
+	flagSet := NewFlagSet()
+	var stringData string
+	var enumData string
+	var boolData bool
+
+	flagSet.SetGroup("Input", "Input Options")
+	flagSet.StringVarP(&stringData, "string-value", "sv", "test", "String value example").Group("Input")
+	flagSet.EnumVarP(&enumData, "enum-value", "ev", EnumVariable(0), "Enum value example", AllowdTypes{
+		"zero": EnumVariable(0),
+		"one":  EnumVariable(1),
+	}).Group("Input")
+	flagSet.BoolVar(&boolData, "bool-value", false, "Bool value example")
+
+	output := &bytes.Buffer{}
+	err := flagSet.MarkdownDoc(output)
+	require.Nil(t, err)
+
+	expected := "## Input Options\n\n" +
+		"| Flag | Type | Default | Description |\n" +
+		"|------|------|---------|-------------|\n" +
+		"| `-sv, -string-value` | string | \"test\" | String value example |\n" +
+		"| `-ev, -enum-value` | value | zero | Enum value example (allowed: one, zero) |\n" +
+		"\n" +
+		"## other options\n\n" +
+		"| Flag | Type | Default | Description |\n" +
+		"|------|------|---------|-------------|\n" +
+		"| `-bool-value` | bool |  | Bool value example |\n"
+	require.Equal(t, expected, output.String())
+	tearDown(t.Name())
+}
@@ -0,0 +1,40 @@
+package goflags
+
+import "fmt"
+
+// ParseErrorCategory is the kind of failure reported by a ParseError
+type ParseErrorCategory string
+
+const (
+	// CategoryRequired is used when a required flag is not provided
+	CategoryRequired ParseErrorCategory = "required"
+	// CategoryInvalid is used when a flag value fails validation
+	CategoryInvalid ParseErrorCategory = "invalid"
+	// CategoryUnknown is used when an unknown flag is given
+	CategoryUnknown ParseErrorCategory = "unknown"
+	// CategoryExclusive is used when mutually exclusive flags are given together
+	CategoryExclusive ParseErrorCategory = "exclusive"
+)
+
+// ParseError is returned by Parse when a flag fails validation.
+// It can be extracted from the returned error with errors.As.
+type ParseError struct {
+	// Flag is the canonical name of the offending flag
+	Flag string
+	// Value is the offending value, empty if not applicable
+	Value    string
+	Category ParseErrorCategory
+	Err      error
+}
+
+func newParseError(category ParseErrorCategory, flag, value string, format string, args ...interface{}) *ParseError {
+	return &ParseError{Flag: flag, Value: value, Category: category, Err: fmt.Errorf(format, args...)}
+}
+
+func (e *ParseError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
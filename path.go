@@ -3,7 +3,10 @@ package goflags
 import (
 	"os"
 	"path/filepath"
+	"runtime"
 	"strings"
+
+	fileutil "github.com/projectdiscovery/utils/file"
 )
 
 // GetConfigFilePath returns the config file path
@@ -12,7 +15,23 @@ func (flagSet *FlagSet) GetConfigFilePath() (string, error) {
 	if flagSet.configFilePath != "" {
 		return flagSet.configFilePath, nil
 	}
-	// generate default config name
+	configDir, err := flagSet.GetToolConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "config.yaml"), nil
+}
+
+// GetToolConfigDir returns the config directory of the tool,
+// $HOME/.config/<app name> unless overridden with SetConfigDir.
+//
+// On non-windows platforms $XDG_CONFIG_HOME/<app name> is used when
+// XDG_CONFIG_HOME is set, unless only the $HOME/.config/<app name>
+// directory exists (ex: created by a previous version).
+func (flagSet *FlagSet) GetToolConfigDir() (string, error) {
+	if flagSet.configDir != "" {
+		return flagSet.configDir, nil
+	}
 	appName := filepath.Base(os.Args[0])
 	// trim extension from app name
 	appName = strings.TrimSuffix(appName, filepath.Ext(appName))
@@ -20,7 +39,22 @@ func (flagSet *FlagSet) GetConfigFilePath() (string, error) {
 	if err != nil {
 		return "", err
 	}
-	return filepath.Join(homePath, ".config", appName, "config.yaml"), nil
+	legacyDir := filepath.Join(homePath, ".config", appName)
+
+	// relative paths are invalid per the XDG base directory specification
+	if xdgConfigHome := os.Getenv("XDG_CONFIG_HOME"); runtime.GOOS != "windows" && filepath.IsAbs(xdgConfigHome) {
+		xdgDir := filepath.Join(xdgConfigHome, appName)
+		if !fileutil.FolderExists(xdgDir) && fileutil.FolderExists(legacyDir) {
+			return legacyDir, nil
+		}
+		return xdgDir, nil
+	}
+	return legacyDir, nil
+}
+
+// SetConfigDir sets custom tool config directory where the default config is loaded from and created
+func (flagSet *FlagSet) SetConfigDir(dir string) {
+	flagSet.configDir = dir
 }
 
 // SetConfigFilePath sets custom config file path
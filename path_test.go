@@ -2,9 +2,13 @@ package goflags
 
 import (
 	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestFlagSet_SetConfigFilePath(t *testing.T) {
@@ -25,3 +29,68 @@ func TestFlagSet_SetConfigFilePath(t *testing.T) {
 	assert.Equal(t, configFilePath, gotFilePath)
 	tearDown(t.Name())
 }
+
+func TestFlagSet_SetConfigDir(t *testing.T) {
+	configDir := filepath.Join(t.TempDir(), "tool")
+	flagSet := NewFlagSet()
+
+	var stringData string
+	flagSet.StringVar(&stringData, "string-value", "default", "String value example")
+	flagSet.SetConfigDir(configDir)
+
+	gotConfigDir, err := flagSet.GetToolConfigDir()
+	require.Nil(t, err)
+	require.Equal(t, configDir, gotConfigDir)
+
+	err = flagSet.ParseArgs(nil)
+	require.Nil(t, err)
+	gotFilePath, err := flagSet.GetConfigFilePath()
+	require.Nil(t, err)
+	require.Equal(t, filepath.Join(configDir, "config.yaml"), gotFilePath)
+	require.FileExists(t, gotFilePath, "could not create config in custom config dir")
+	tearDown(t.Name())
+}
+
+func TestFlagSet_GetConfigFilePathBeforeParse(t *testing.T) {
+	configDir := filepath.Join(t.TempDir(), "tool")
+	flagSet := NewFlagSet()
+	flagSet.SetConfigDir(configDir)
+
+	gotFilePath, err := flagSet.GetConfigFilePath()
+	require.Nil(t, err)
+	require.Equal(t, filepath.Join(configDir, "config.yaml"), gotFilePath, "config file path should reflect the config dir override")
+	require.NoFileExists(t, gotFilePath, "config file should not be created before parse")
+	tearDown(t.Name())
+}
+
+func TestFlagSet_GetToolConfigDirXDG(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("XDG_CONFIG_HOME is not used on windows")
+	}
+	appName := filepath.Base(os.Args[0])
+	appName = strings.TrimSuffix(appName, filepath.Ext(appName))
+
+	homeDir := t.TempDir()
+	xdgDir := t.TempDir()
+	t.Setenv("HOME", homeDir)
+	t.Setenv("XDG_CONFIG_HOME", xdgDir)
+
+	flagSet := NewFlagSet()
+	gotConfigDir, err := flagSet.GetToolConfigDir()
+	require.Nil(t, err)
+	require.Equal(t, filepath.Join(xdgDir, appName), gotConfigDir, "config dir should be under XDG_CONFIG_HOME")
+
+	// an existing config dir of the previous location keeps being used
+	legacyDir := filepath.Join(homeDir, ".config", appName)
+	require.Nil(t, os.MkdirAll(legacyDir, os.ModePerm))
+	gotConfigDir, err = flagSet.GetToolConfigDir()
+	require.Nil(t, err)
+	require.Equal(t, legacyDir, gotConfigDir)
+
+	// relative XDG_CONFIG_HOME values are ignored
+	t.Setenv("XDG_CONFIG_HOME", "relative")
+	gotConfigDir, err = flagSet.GetToolConfigDir()
+	require.Nil(t, err)
+	require.Equal(t, legacyDir, gotConfigDir)
+	tearDown(t.Name())
+}
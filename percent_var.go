@@ -0,0 +1,68 @@
+package goflags
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type percentValue float64
+
+func newPercentValue(val float64, p *float64) *percentValue {
+	*p = val
+	return (*percentValue)(p)
+}
+
+func (p *percentValue) Set(s string) error {
+	value := strings.TrimSuffix(strings.TrimSpace(s), "%")
+	percent, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return fmt.Errorf("invalid percentage %q", s)
+	}
+	if percent < 0 || percent > 100 {
+		return fmt.Errorf("invalid percentage %q: must be between 0%% and 100%%", s)
+	}
+	*p = percentValue(percent / 100)
+	return nil
+}
+
+func (p *percentValue) String() string {
+	return strconv.FormatFloat(float64(*p)*100, 'g', 10, 64) + "%"
+}
+
+func (p *percentValue) displayType() string { return "string" }
+
+// PercentVar adds a percentage flag with a longname
+func (flagSet *FlagSet) PercentVar(field *float64, long string, defaultValue string, usage string) *FlagData {
+	return flagSet.PercentVarP(field, long, "", defaultValue, usage)
+}
+
+// PercentVarP adds a percentage flag with a shortname and longname.
+// The value is a number between 0 and 100 with an optional % suffix (ex: 25%)
+// and is stored as a fraction between 0 and 1 (ex: 0.25).
+func (flagSet *FlagSet) PercentVarP(field *float64, long, short string, defaultValue string, usage string) *FlagData {
+	if field == nil {
+		panic(fmt.Errorf("field cannot be nil for flag -%v", long))
+	}
+	if defaultValue != "" {
+		if err := (*percentValue)(field).Set(defaultValue); err != nil {
+			panic(fmt.Errorf("failed to set default value for flag -%v: %v", long, err))
+		}
+	}
+	flagData := &FlagData{
+		usage:        usage,
+		long:         long,
+		defaultValue: defaultValue,
+	}
+	if !flagSet.checkDuplicateFlag(long, short) {
+		return flagData
+	}
+	if short != "" {
+		flagData.short = short
+		flagSet.CommandLine.Var(newPercentValue(*field, field), short, usage)
+		flagSet.setFlagKey(short, flagData)
+	}
+	flagSet.CommandLine.Var(newPercentValue(*field, field), long, usage)
+	flagSet.setFlagKey(long, flagData)
+	return flagData
+}
@@ -0,0 +1,66 @@
+package goflags
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	permissionutil "github.com/projectdiscovery/utils/permission"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPercentVar(t *testing.T) {
+	t.Run("valid-percent", func(t *testing.T) {
+		tests := map[string]float64{
+			"25%":  0.25,
+			"100%": 1,
+			"0":    0,
+			"50":   0.5,
+		}
+		for value, expected := range tests {
+			var sample float64
+			flagSet := NewFlagSet()
+			flagSet.PercentVarP(&sample, "sample", "s", "", "sampling percentage")
+			err := flagSet.ParseArgs([]string{"-sample", value})
+			assert.Nil(t, err)
+			assert.Equal(t, expected, sample, "could not get correct fraction for %v", value)
+			tearDown(t.Name())
+		}
+	})
+
+	t.Run("out-of-range", func(t *testing.T) {
+		var sample float64
+		err := newPercentValue(0, &sample).Set("150%")
+		assert.EqualError(t, err, `invalid percentage "150%": must be between 0% and 100%`)
+		tearDown(t.Name())
+	})
+
+	t.Run("config-file", func(t *testing.T) {
+		var sample, rate float64
+		flagSet := NewFlagSet()
+		flagSet.PercentVar(&sample, "sample", "10%", "sampling percentage")
+		flagSet.PercentVar(&rate, "rate", "", "rate percentage")
+
+		err := os.WriteFile("test.yaml", []byte("sample: 25%\nrate: 12.5"), permissionutil.ConfigFilePermission)
+		require.Nil(t, err, "could not write temporary config")
+		defer os.Remove("test.yaml")
+
+		err = flagSet.MergeConfigFile("test.yaml")
+		require.Nil(t, err, "could not merge temporary config")
+		assert.Equal(t, 0.25, sample)
+		assert.Equal(t, 0.125, rate)
+		tearDown(t.Name())
+	})
+
+	t.Run("usage-type", func(t *testing.T) {
+		var sample float64
+		flagSet := NewFlagSet()
+		flagSet.PercentVar(&sample, "sample", "25%", "sampling percentage")
+		flag := flagSet.CommandLine.Lookup("sample")
+		displayType, _ := usageTypeAndDescription(flag, reflect.TypeOf(flag.Value))
+		assert.Equal(t, "string", displayType)
+		assert.Equal(t, "25%", flag.DefValue)
+		tearDown(t.Name())
+	})
+}
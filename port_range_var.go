@@ -0,0 +1,145 @@
+package goflags
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	minPort = 1
+	maxPort = 65535
+)
+
+type portRangeValue struct {
+	value     *[]int
+	isDefault bool
+}
+
+// Set parses comma separated ports and port ranges (ex: 80,443,8000-9000)
+// and merges them into the sorted list of unique ports.
+func (p *portRangeValue) Set(value string) error {
+	ports := make(map[int]struct{})
+	if !p.isDefault {
+		for _, port := range *p.value {
+			ports[port] = struct{}{}
+		}
+	}
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		start, end, err := parsePortRangeItem(item)
+		if err != nil {
+			return err
+		}
+		for port := start; port <= end; port++ {
+			ports[port] = struct{}{}
+		}
+	}
+
+	result := make([]int, 0, len(ports))
+	for port := range ports {
+		result = append(result, port)
+	}
+	sort.Ints(result)
+	*p.value = result
+	p.isDefault = false
+	return nil
+}
+
+// String returns the ports with consecutive ports collapsed into ranges
+func (p *portRangeValue) String() string {
+	if p.value == nil {
+		return ""
+	}
+	var items []string
+	ports := *p.value
+	for i := 0; i < len(ports); i++ {
+		start := ports[i]
+		for i+1 < len(ports) && ports[i+1] == ports[i]+1 {
+			i++
+		}
+		if start == ports[i] {
+			items = append(items, strconv.Itoa(start))
+		} else {
+			items = append(items, fmt.Sprintf("%d-%d", start, ports[i]))
+		}
+	}
+	return strings.Join(items, ",")
+}
+
+func (p *portRangeValue) Get() any { return append([]int(nil), *p.value...) }
+
+func (p *portRangeValue) displayType() string { return "string" }
+
+func parsePortRangeItem(item string) (int, int, error) {
+	first, second, isRange := strings.Cut(item, "-")
+	start, err := parsePortNumber(first)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid port %q: %v", item, err)
+	}
+	if !isRange {
+		return start, start, nil
+	}
+	end, err := parsePortNumber(second)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid port range %q: %v", item, err)
+	}
+	if start > end {
+		return 0, 0, fmt.Errorf("invalid port range %q: start port is greater than end port", item)
+	}
+	return start, end, nil
+}
+
+func parsePortNumber(value string) (int, error) {
+	port, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return 0, fmt.Errorf("not a number")
+	}
+	if port < minPort || port > maxPort {
+		return 0, fmt.Errorf("port must be between %d and %d", minPort, maxPort)
+	}
+	return port, nil
+}
+
+// PortRangeVar adds a port range flag with a longname
+func (flagSet *FlagSet) PortRangeVar(field *[]int, long string, defaultValue string, usage string) *FlagData {
+	return flagSet.PortRangeVarP(field, long, "", defaultValue, usage)
+}
+
+// PortRangeVarP adds a port range flag with a shortname and longname.
+// It accepts comma separated ports and ascending ranges (ex: 80,443,8000-9000)
+// stored as a sorted list of unique ports. Values provided on the command line replace the default ones.
+func (flagSet *FlagSet) PortRangeVarP(field *[]int, long, short string, defaultValue string, usage string) *FlagData {
+	if field == nil {
+		panic(fmt.Errorf("field cannot be nil for flag -%v", long))
+	}
+	value := &portRangeValue{value: field}
+	*field = nil
+	if defaultValue != "" {
+		if err := value.Set(defaultValue); err != nil {
+			panic(fmt.Errorf("failed to set default value for flag -%v: %v", long, err))
+		}
+	}
+	value.isDefault = true
+
+	flagData := &FlagData{
+		usage:        usage,
+		long:         long,
+		defaultValue: defaultValue,
+	}
+	if !flagSet.checkDuplicateFlag(long, short) {
+		return flagData
+	}
+	if short != "" {
+		flagData.short = short
+		flagSet.CommandLine.Var(value, short, usage)
+		flagSet.setFlagKey(short, flagData)
+	}
+	flagSet.CommandLine.Var(value, long, usage)
+	flagSet.setFlagKey(long, flagData)
+	return flagData
+}
@@ -0,0 +1,75 @@
+package goflags
+
+import (
+	"os"
+	"testing"
+
+	permissionutil "github.com/projectdiscovery/utils/permission"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPortRangeVar(t *testing.T) {
+	t.Run("single-ports", func(t *testing.T) {
+		var ports []int
+		flagSet := NewFlagSet()
+		flagSet.CreateGroup("Port", "Port",
+			flagSet.PortRangeVarP(&ports, "port", "p", "22", "ports to scan"),
+		)
+		os.Args = []string{
+			os.Args[0],
+			"-p", "443,80",
+			"-p", "8080",
+		}
+		err := flagSet.Parse()
+		assert.Nil(t, err)
+		assert.Equal(t, []int{80, 443, 8080}, ports)
+		tearDown(t.Name())
+	})
+
+	t.Run("ranges-and-overlaps", func(t *testing.T) {
+		var ports []int
+		value := &portRangeValue{value: &ports}
+		err := value.Set("8000-8003, 8002-8005,80,8001")
+		assert.Nil(t, err)
+		assert.Equal(t, []int{80, 8000, 8001, 8002, 8003, 8004, 8005}, ports)
+		assert.Equal(t, "80,8000-8005", value.String())
+		tearDown(t.Name())
+	})
+
+	t.Run("config-file", func(t *testing.T) {
+		var ports []int
+		flagSet := NewFlagSet()
+		flagSet.PortRangeVar(&ports, "port", "22", "ports to scan")
+
+		configFileData := `
+port:
+ - 443
+ - 8000-8002`
+		err := os.WriteFile("test.yaml", []byte(configFileData), permissionutil.ConfigFilePermission)
+		require.Nil(t, err, "could not write temporary config")
+		defer os.Remove("test.yaml")
+
+		err = flagSet.MergeConfigFile("test.yaml")
+		require.Nil(t, err, "could not merge temporary config")
+		assert.Equal(t, []int{443, 8000, 8001, 8002}, ports)
+		tearDown(t.Name())
+	})
+
+	t.Run("invalid-ports", func(t *testing.T) {
+		invalidTests := map[string]string{
+			"0":          `invalid port "0"`,
+			"65536":      `invalid port "65536"`,
+			"80,http":    `invalid port "http"`,
+			"9000-8000":  `invalid port range "9000-8000"`,
+			"8000-70000": `invalid port range "8000-70000"`,
+		}
+		for value, expected := range invalidTests {
+			var ports []int
+			err := (&portRangeValue{value: &ports}).Set(value)
+			assert.NotNil(t, err)
+			assert.ErrorContains(t, err, expected)
+		}
+		tearDown(t.Name())
+	})
+}
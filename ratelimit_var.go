@@ -126,13 +126,16 @@ func (flagSet *FlagSet) RateLimitMapVarP(field *RateLimitMap, long, short string
 		defaultValue: defaultValue,
 		skipMarshal:  true,
 	}
+	if !flagSet.checkDuplicateFlag(long, short) {
+		return flagData
+	}
 	if short != "" {
 		flagData.short = short
 		flagSet.CommandLine.Var(field, short, usage)
-		flagSet.flagKeys.Set(short, flagData)
+		flagSet.setFlagKey(short, flagData)
 	}
 	flagSet.CommandLine.Var(field, long, usage)
-	flagSet.flagKeys.Set(long, flagData)
+	flagSet.setFlagKey(long, flagData)
 	return flagData
 }
 
@@ -0,0 +1,66 @@
+package goflags
+
+import (
+	"fmt"
+	"regexp"
+)
+
+type regexpValue struct {
+	value **regexp.Regexp
+}
+
+func (r *regexpValue) Set(s string) error {
+	compiled, err := regexp.Compile(s)
+	if err != nil {
+		return err
+	}
+	*r.value = compiled
+	return nil
+}
+
+func (r *regexpValue) Get() any { return *r.value }
+
+func (r *regexpValue) String() string {
+	if r.value == nil || *r.value == nil {
+		return ""
+	}
+	return (*r.value).String()
+}
+
+func (r *regexpValue) displayType() string { return "string" }
+
+// RegexpVar adds a regular expression flag with a longname
+func (flagSet *FlagSet) RegexpVar(field **regexp.Regexp, long string, defaultValue string, usage string) *FlagData {
+	return flagSet.RegexpVarP(field, long, "", defaultValue, usage)
+}
+
+// RegexpVarP adds a regular expression flag with a shortname and longname.
+// The value is compiled once with regexp.Compile and is nil if not set.
+func (flagSet *FlagSet) RegexpVarP(field **regexp.Regexp, long, short string, defaultValue string, usage string) *FlagData {
+	if field == nil {
+		panic(fmt.Errorf("field cannot be nil for flag -%v", long))
+	}
+	value := &regexpValue{value: field}
+	*field = nil
+	if defaultValue != "" {
+		if err := value.Set(defaultValue); err != nil {
+			panic(fmt.Errorf("failed to set default value for flag -%v: %v", long, err))
+		}
+	}
+	flagData := &FlagData{
+		usage:        usage,
+		long:         long,
+		defaultValue: defaultValue,
+	}
+	if !flagSet.checkDuplicateFlag(long, short) {
+		return flagData
+	}
+	if short != "" {
+		flagData.short = short
+		flagSet.CommandLine.Var(value, short, usage)
+		flagSet.setFlagKey(short, flagData)
+	}
+	flagSet.CommandLine.Var(value, long, usage)
+	flagSet.setFlagKey(long, flagData)
+	return flagData
+}
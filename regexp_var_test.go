@@ -0,0 +1,60 @@
+package goflags
+
+import (
+	"os"
+	"reflect"
+	"regexp"
+	"testing"
+
+	permissionutil "github.com/projectdiscovery/utils/permission"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegexpVar(t *testing.T) {
+	t.Run("valid-pattern", func(t *testing.T) {
+		var match *regexp.Regexp
+		flagSet := NewFlagSet()
+		flagSet.RegexpVarP(&match, "match", "m", "", "pattern to match")
+		err := flagSet.ParseArgs([]string{"-match", "^admin"})
+		assert.Nil(t, err)
+		require.NotNil(t, match)
+		assert.True(t, match.MatchString("admin-panel"))
+		assert.False(t, match.MatchString("user-admin"))
+		tearDown(t.Name())
+	})
+
+	t.Run("invalid-pattern", func(t *testing.T) {
+		var match *regexp.Regexp
+		flagSet := NewFlagSet()
+		flagSet.RegexpVar(&match, "match", "", "pattern to match")
+		err := flagSet.Set("match", "(admin")
+		assert.EqualError(t, err, "invalid value \"(admin\" for flag -match: error parsing regexp: missing closing ): `(admin`")
+		assert.Nil(t, match)
+
+		assert.PanicsWithError(t, "failed to set default value for flag -pattern: error parsing regexp: missing closing ): `(admin`", func() {
+			flagSet.RegexpVar(&match, "pattern", "(admin", "pattern to match")
+		})
+		tearDown(t.Name())
+	})
+
+	t.Run("config-file", func(t *testing.T) {
+		var match *regexp.Regexp
+		flagSet := NewFlagSet()
+		flagSet.RegexpVar(&match, "match", "", "pattern to match")
+
+		err := os.WriteFile("test.yaml", []byte("match: '^admin'"), permissionutil.ConfigFilePermission)
+		require.Nil(t, err, "could not write temporary config")
+		defer os.Remove("test.yaml")
+
+		err = flagSet.MergeConfigFile("test.yaml")
+		require.Nil(t, err, "could not merge temporary config")
+		require.NotNil(t, match)
+		assert.Equal(t, "^admin", match.String())
+
+		flag := flagSet.CommandLine.Lookup("match")
+		displayType, _ := usageTypeAndDescription(flag, reflect.TypeOf(flag.Value))
+		assert.Equal(t, "string", displayType)
+		tearDown(t.Name())
+	})
+}
@@ -0,0 +1,139 @@
+package goflags
+
+import (
+	"flag"
+	"net"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	sliceutil "github.com/projectdiscovery/utils/slice"
+	"golang.org/x/exp/maps"
+)
+
+// Reset reverts the flag variables to their registered defaults and clears
+// the parse state (provided flags, config sources) so the flagSet can be
+// parsed again without registering the flags again.
+//
+// NOTE: custom flag.Value flags are reset by setting their default string,
+// dynamic flags are reset to the zero value of their field.
+func (flagSet *FlagSet) Reset() {
+	flagSet.CommandLine = copyCommandLine(flagSet.CommandLine)
+	flagSet.configFlags = make(map[*FlagData]struct{})
+	flagSet.configExtras = nil
+
+	flagSet.flagKeys.forEach(func(key string, data *FlagData) {
+		if key != data.canonicalName() {
+			return
+		}
+		// defaults replaced by DefaultFromConfig are restored
+		if data.registeredDefValue != nil {
+			for _, name := range []string{data.short, data.long} {
+				if currentFlag := flagSet.CommandLine.Lookup(name); currentFlag != nil {
+					currentFlag.DefValue = *data.registeredDefValue
+				}
+			}
+			data.registeredDefValue = nil
+		}
+		if currentFlag := flagSet.CommandLine.Lookup(key); currentFlag != nil {
+			resetFlagValue(currentFlag, data)
+		}
+	})
+	flagSet.configOnlyKeys.forEach(func(key string, data *FlagData) {
+		if stringSlice, ok := data.field.(*StringSlice); ok {
+			*stringSlice = nil
+			if defaultValue, ok := data.defaultValue.([]string); ok {
+				for _, item := range defaultValue {
+					_ = stringSlice.Set(item)
+				}
+			}
+		}
+	})
+}
+
+// copyCommandLine returns a new flag set with the flags of the given one
+// registered with their defaults but without parse state.
+func copyCommandLine(commandLine *flag.FlagSet, skipNames ...string) *flag.FlagSet {
+	newCommandLine := flag.NewFlagSet(commandLine.Name(), commandLine.ErrorHandling())
+	newCommandLine.SetOutput(commandLine.Output())
+	newCommandLine.Usage = commandLine.Usage
+	commandLine.VisitAll(func(fl *flag.Flag) {
+		if sliceutil.Contains(skipNames, fl.Name) {
+			return
+		}
+		newCommandLine.Var(fl.Value, fl.Name, fl.Usage)
+		// keep the registered default even if the flag set was already parsed
+		newCommandLine.Lookup(fl.Name).DefValue = fl.DefValue
+	})
+	return newCommandLine
+}
+
+// resetFlagValue reverts the value of a flag to its registered default
+func resetFlagValue(currentFlag *flag.Flag, data *FlagData) {
+	defValue := currentFlag.DefValue
+	switch value := currentFlag.Value.(type) {
+	case *callBackVar:
+		// callbacks have no state
+	case *dynamicFlag:
+		field := reflect.ValueOf(value.field).Elem()
+		field.Set(reflect.Zero(field.Type()))
+	case *StringSlice:
+		*value = append(StringSlice{}, optionDefaultValues[value]...)
+	case *EnumSliceVar:
+		*value.value = nil
+		if defValue != "" {
+			*value.value = strings.Split(defValue, ",")
+		}
+	case *RuntimeMap:
+		value.kv = nil
+		if defaultValue, ok := data.defaultValue.([]string); ok {
+			for _, item := range defaultValue {
+				_ = value.Set(item)
+			}
+		}
+	case *RateLimitMap:
+		value.kv = nil
+		if defaultValue, ok := data.defaultValue.(StringSlice); ok {
+			for _, defaultItem := range defaultValue {
+				items, _ := ToStringSlice(defaultItem, rateLimitOptionMap[value])
+				for _, item := range items {
+					_ = value.Set(item)
+				}
+			}
+		}
+	case *portRangeValue:
+		*value.value = nil
+		value.isDefault = true
+		if defValue != "" {
+			_ = value.Set(defValue)
+		}
+		value.isDefault = true
+	case *Port:
+		value.kv = maps.Clone(portOptionDefaultValues[value])
+	case *Size:
+		size, _ := strconv.ParseInt(defValue, 10, 64)
+		*value = Size(size)
+	case *urlValue:
+		*value = urlValue(defValue)
+	case *ipValue:
+		*value = ipValue(net.ParseIP(defValue))
+	case *timeValue:
+		*value.value = time.Time{}
+		if defValue != "" {
+			_ = value.Set(defValue)
+		}
+	case *regexpValue:
+		*value.value = nil
+		if defValue != "" {
+			_ = value.Set(defValue)
+		}
+	case *cidrValue:
+		*value.value = net.IPNet{}
+		if defValue != "" {
+			_ = value.Set(defValue)
+		}
+	default:
+		_ = value.Set(defValue)
+	}
+}
@@ -0,0 +1,106 @@
+package goflags
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	permissionutil "github.com/projectdiscovery/utils/permission"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReset(t *testing.T) {
+	err := os.WriteFile("test.yaml", []byte("int-value: 543"), permissionutil.ConfigFilePermission)
+	require.Nil(t, err, "could not write temporary config")
+	defer os.Remove("test.yaml")
+
+	flagSet := NewFlagSet()
+	flagSet.SetConfigFilePath("test.yaml")
+	var (
+		stringData   string
+		intData      int
+		boolData     bool
+		durationData time.Duration
+		sliceData    StringSlice
+		enumData     string
+		portsData    []int
+		sizeData     Size
+		countData    int
+	)
+	flagSet.StringVarP(&stringData, "string-value", "sv", "default", "String value example")
+	flagSet.IntVar(&intData, "int-value", 10, "Int value example")
+	flagSet.BoolVar(&boolData, "bool-value", false, "Bool value example")
+	flagSet.DurationVar(&durationData, "duration-value", time.Second, "Duration value example")
+	flagSet.StringSliceVar(&sliceData, "slice-value", []string{"a", "b"}, "Slice value example", CommaSeparatedStringSliceOptions)
+	flagSet.EnumVar(&enumData, "enum-value", EnumVariable(0), "Enum value example", AllowdTypes{"zero": EnumVariable(0), "one": EnumVariable(1)})
+	flagSet.PortRangeVar(&portsData, "ports", "80,443", "Ports example")
+	flagSet.SizeVar(&sizeData, "size-value", "2kb", "Size value example")
+	flagSet.CountVarP(&countData, "verbose", "v", 0, "Verbosity example")
+
+	err = flagSet.ParseArgs([]string{
+		"-sv", "test", "-bool-value", "-duration-value", "1m", "-slice-value", "c",
+		"-enum-value", "one", "-ports", "8080", "-size-value", "1kb", "-v", "-v",
+	})
+	require.Nil(t, err)
+	require.Nil(t, flagSet.Set("int-value", "5"))
+	require.Len(t, flagSet.ProvidedFlags(), 9)
+
+	flagSet.Reset()
+	require.Equal(t, "default", stringData)
+	require.Equal(t, 10, intData)
+	require.False(t, boolData)
+	require.Equal(t, time.Second, durationData)
+	require.Equal(t, StringSlice{"a", "b"}, sliceData)
+	require.Equal(t, "zero", enumData)
+	require.Equal(t, []int{80, 443}, portsData)
+	require.Equal(t, Size(2048), sizeData)
+	require.Equal(t, 0, countData)
+	require.Empty(t, flagSet.ProvidedFlags(), "could not clear provided flags")
+
+	err = flagSet.ParseArgs([]string{"-slice-value", "d", "-ports", "22"})
+	require.Nil(t, err)
+	require.Equal(t, StringSlice{"d"}, sliceData, "could not replace slice defaults after reset")
+	require.Equal(t, []int{22}, portsData, "could not replace port defaults after reset")
+	require.Equal(t, 543, intData, "could not merge config after reset")
+	require.Equal(t, []string{"int-value", "slice-value", "ports"}, flagSet.ProvidedFlags())
+	tearDown(t.Name())
+}
+
+func TestResetPort(t *testing.T) {
+	flagSet := NewFlagSet()
+	flagSet.DisableConfigLoading(true)
+	var ports Port
+	flagSet.PortVarP(&ports, "port", "p", []string{"80"}, "Port example")
+
+	require.Nil(t, flagSet.ParseArgs([]string{"-port", "22"}))
+	require.ElementsMatch(t, []int{22}, ports.AsPorts())
+
+	flagSet.Reset()
+	require.ElementsMatch(t, []int{80}, ports.AsPorts(), "could not restore port defaults")
+
+	require.Nil(t, flagSet.ParseArgs([]string{"-p", "443"}))
+	require.ElementsMatch(t, []int{443}, ports.AsPorts(), "could not replace port defaults after reset")
+	tearDown(t.Name())
+}
+
+func TestResetDefaultFromConfig(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	require.Nil(t, os.WriteFile(configFile, []byte("defaults:\n  timeout: 30"), permissionutil.ConfigFilePermission))
+
+	flagSet := NewFlagSet()
+	flagSet.SetConfigFilePath(configFile)
+	var timeout int
+	flagSet.IntVar(&timeout, "timeout", 10, "Timeout example").DefaultFromConfig("defaults.timeout")
+
+	require.Nil(t, flagSet.ParseArgs(nil))
+	require.Equal(t, 30, timeout)
+
+	flagSet.Reset()
+	require.Equal(t, 10, timeout, "could not restore registered default")
+	require.Equal(t, "10", flagSet.CommandLine.Lookup("timeout").DefValue)
+
+	require.Nil(t, flagSet.ParseArgs(nil))
+	require.Equal(t, 30, timeout, "could not apply config default after reset")
+	tearDown(t.Name())
+}
@@ -12,9 +12,14 @@ const (
 	kvSep = "="
 )
 
+// defaultRuntimeMapSeparators are the key-value separators accepted by a
+// RuntimeMap unless configured otherwise with SetSeparators
+var defaultRuntimeMapSeparators = []string{kvSep}
+
 // RuntimeMap is a runtime only map of interfaces
 type RuntimeMap struct {
-	kv map[string]interface{}
+	kv         map[string]interface{}
+	separators []string
 }
 
 func (runtimeMap RuntimeMap) String() string {
@@ -30,15 +35,36 @@ func (runtimeMap RuntimeMap) String() string {
 	return defaultBuilder.String()
 }
 
-// Set inserts a value to the map. Format: key=value
+// SetSeparators sets the active key-value separators for the map, "=" by
+// default (ex: SetSeparators("=", ":") to also accept key:value).
+// The value is split on the first occurrence of any active separator.
+//
+// Separators must be set before registering the flag for them to apply
+// to default values as well.
+func (runtimeMap *RuntimeMap) SetSeparators(separators ...string) {
+	runtimeMap.separators = separators
+}
+
+// Set inserts a value to the map. Format: key=value, or key:value
+// when enabled with SetSeparators
 func (runtimeMap *RuntimeMap) Set(value string) error {
 	if runtimeMap.kv == nil {
 		runtimeMap.kv = make(map[string]interface{})
 	}
+	separators := runtimeMap.separators
+	if len(separators) == 0 {
+		separators = defaultRuntimeMapSeparators
+	}
+	idxSep, sepLen := -1, 0
+	for _, separator := range separators {
+		if idx := strings.Index(value, separator); idx >= 0 && (idxSep == -1 || idx < idxSep) {
+			idxSep, sepLen = idx, len(separator)
+		}
+	}
 	var k, v string
-	if idxSep := strings.Index(value, kvSep); idxSep > 0 {
+	if idxSep > 0 {
 		k = value[:idxSep]
-		v = value[idxSep+1:]
+		v = value[idxSep+sepLen:]
 	}
 	// note:
 	// - inserting multiple times the same key will override the previous value
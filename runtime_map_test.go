@@ -14,3 +14,24 @@ func TestRuntimeMap(t *testing.T) {
 	returned := data.AsMap()["variable"]
 	require.Equal(t, "value", returned, "could not get correct return")
 }
+
+func TestRuntimeMapSeparators(t *testing.T) {
+	data := &RuntimeMap{}
+	require.NoError(t, data.Set("Host:a=b"), "could not set key-value")
+	require.NoError(t, data.Set("url=http://example.com"), "could not set key-value")
+	require.Equal(t, map[string]interface{}{
+		"Host:a": "b",
+		"url":    "http://example.com",
+	}, data.AsMap(), "colon should not be a separator by default")
+
+	data = &RuntimeMap{}
+	data.SetSeparators("=", ":")
+	require.NoError(t, data.Set("header1:value1"), "could not set colon separated key-value")
+	require.NoError(t, data.Set("header2=value2"), "could not set equals separated key-value")
+	require.NoError(t, data.Set("header3=http://example.com"), "could not set key-value")
+	require.Equal(t, map[string]interface{}{
+		"header1": "value1",
+		"header2": "value2",
+		"header3": "http://example.com",
+	}, data.AsMap(), "could not get correct map")
+}
@@ -0,0 +1,65 @@
+package goflags
+
+import (
+	"encoding/json"
+	"io"
+	"reflect"
+)
+
+// FlagSchema is the machine readable metadata of a flag
+type FlagSchema struct {
+	Name          string   `json:"name"`
+	Short         string   `json:"short"`
+	Long          string   `json:"long"`
+	Type          string   `json:"type"`
+	Default       string   `json:"default"`
+	Group         string   `json:"group"`
+	Usage         string   `json:"usage"`
+	Required      bool     `json:"required"`
+	Deprecated    bool     `json:"deprecated,omitempty"`
+	AllowedValues []string `json:"allowedValues,omitempty"`
+	Since         string   `json:"since,omitempty"`
+}
+
+// SchemaJSON writes the metadata of the registered flags as a JSON array
+// in registration order (or alphabetical order if enabled with SetSortFlags).
+func (flagSet *FlagSet) SchemaJSON(w io.Writer) error {
+	schema := []FlagSchema{}
+	seen := make(map[*FlagData]struct{})
+	flagSet.forEachUsageFlag(func(key string, data *FlagData) {
+		currentFlag := flagSet.CommandLine.Lookup(key)
+		if currentFlag == nil || flagSet.isHidden(data) {
+			return
+		}
+		if _, ok := seen[data]; ok {
+			return
+		}
+		seen[data] = struct{}{}
+
+		flagDisplayType, usage := usageTypeAndDescription(currentFlag, reflect.TypeOf(currentFlag.Value))
+		if flagDisplayType == "" {
+			flagDisplayType = "bool"
+		}
+		defaultValue := currentFlag.DefValue
+		if data.secret && defaultValue != "" {
+			defaultValue = secretMask
+		}
+		schema = append(schema, FlagSchema{
+			Name:          data.canonicalName(),
+			Short:         data.short,
+			Long:          data.long,
+			Type:          flagDisplayType,
+			Default:       defaultValue,
+			Group:         data.group,
+			Usage:         usage,
+			Required:      data.required,
+			Deprecated:    data.deprecated,
+			AllowedValues: enumAllowedValues(currentFlag.Value),
+			Since:         data.since,
+		})
+	})
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(schema)
+}
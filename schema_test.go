@@ -0,0 +1,87 @@
+package goflags
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaJSON(t *testing.T) {
+	flagSet := NewFlagSet()
+	var stringData, legacyData string
+	var enumData string
+
+	flagSet.SetGroup("Input", "Input Options")
+	flagSet.StringVarP(&stringData, "string-value", "sv", "test", "String value example").Group("Input")
+	flagSet.EnumVar(&enumData, "enum-value", EnumVariable(1), "Enum value example", AllowdTypes{
+		"zero": EnumVariable(0),
+		"one":  EnumVariable(1),
+	})
+	flagSet.StringVar(&legacyData, "legacy-value", "", "Legacy value example").Deprecated()
+
+	output := &bytes.Buffer{}
+	err := flagSet.SchemaJSON(output)
+	require.Nil(t, err)
+
+	var schema []FlagSchema
+	err = json.Unmarshal(output.Bytes(), &schema)
+	require.Nil(t, err, "could not unmarshal schema")
+	require.Len(t, schema, 3)
+	require.Equal(t, 1, bytes.Count(output.Bytes(), []byte(`"deprecated": true`)), "only deprecated flags should report the field")
+
+	require.Equal(t, FlagSchema{
+		Name:    "string-value",
+		Short:   "sv",
+		Long:    "string-value",
+		Type:    "string",
+		Default: "test",
+		Group:   "Input",
+		Usage:   "String value example",
+	}, schema[0])
+	require.Equal(t, FlagSchema{
+		Name:          "enum-value",
+		Long:          "enum-value",
+		Type:          "value",
+		Default:       "one",
+		Usage:         "Enum value example",
+		AllowedValues: []string{"one", "zero"},
+	}, schema[1])
+	require.Equal(t, FlagSchema{
+		Name:       "legacy-value",
+		Long:       "legacy-value",
+		Type:       "string",
+		Usage:      "Legacy value example",
+		Deprecated: true,
+	}, schema[2])
+	tearDown(t.Name())
+}
+
+func TestSinceAnnotation(t *testing.T) {
+	flagSet := NewFlagSet()
+	var stringData string
+	flagSet.StringVar(&stringData, "string-value", "", "String value example").Since("v1.4.0")
+
+	output := &bytes.Buffer{}
+	err := flagSet.SchemaJSON(output)
+	require.Nil(t, err)
+	var schema []FlagSchema
+	err = json.Unmarshal(output.Bytes(), &schema)
+	require.Nil(t, err, "could not unmarshal schema")
+	require.Len(t, schema, 1)
+	require.Equal(t, "v1.4.0", schema[0].Since)
+
+	markdown := &bytes.Buffer{}
+	err = flagSet.MarkdownDoc(markdown)
+	require.Nil(t, err)
+	require.Contains(t, markdown.String(), "String value example (since v1.4.0)")
+
+	usage := &bytes.Buffer{}
+	flagSet.CommandLine.SetOutput(usage)
+	flagSet.args = []string{"-h"}
+	flagSet.usageFunc()
+	require.Contains(t, usage.String(), "String value example")
+	require.NotContains(t, usage.String(), "v1.4.0")
+	tearDown(t.Name())
+}
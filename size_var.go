@@ -2,35 +2,79 @@ package goflags
 
 import (
 	"fmt"
+	"math"
 	"strconv"
-
-	fileutil "github.com/projectdiscovery/utils/file"
+	"strings"
 )
 
-type Size int
+// Size is a byte count, parsed from a value with an optional unit
+type Size int64
+
+// sizeUnits maps lowercase size suffixes to their byte length. Decimal
+// suffixes (kb, mb, ...) are aliases of their binary KiB variants, so that
+// 1kb is 1024 bytes like in previous versions.
+var sizeUnits = map[string]int64{
+	"b":   1,
+	"kb":  1 << 10,
+	"kib": 1 << 10,
+	"mb":  1 << 20,
+	"mib": 1 << 20,
+	"gb":  1 << 30,
+	"gib": 1 << 30,
+	"tb":  1 << 40,
+	"tib": 1 << 40,
+}
 
 func (s *Size) Set(size string) error {
-	sizeInBytes, err := fileutil.FileSizeToByteLen(size)
+	sizeInBytes, err := sizeToByteLen(size)
 	if err != nil {
-		return err
+		return fmt.Errorf("invalid size %q: %v", size, err)
 	}
 	*s = Size(sizeInBytes)
 	return nil
 }
 
 func (s *Size) String() string {
-	return strconv.Itoa(int(*s))
+	return strconv.FormatInt(int64(*s), 10)
 }
 
-// SizeVar converts the given fileSize with a unit (kb, mb, gb, or tb) to bytes.
-// For example, '2kb' will be converted to 2048.
+func (s *Size) displayType() string { return "string" }
+
+// sizeToByteLen converts a size with an optional unit to bytes,
+// falling back to mb for values without a unit.
+func sizeToByteLen(size string) (int64, error) {
+	size = strings.ToLower(strings.TrimSpace(size))
+	unitIndex := strings.IndexFunc(size, func(r rune) bool { return r < '0' || r > '9' })
+	if unitIndex == -1 {
+		unitIndex = len(size)
+	}
+	number, unit := size[:unitIndex], strings.TrimSpace(size[unitIndex:])
+	if unit == "" {
+		unit = "mb"
+	}
+	value, err := strconv.ParseInt(number, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parse error: %v", err)
+	}
+	multiplier, ok := sizeUnits[unit]
+	if !ok {
+		return 0, fmt.Errorf("unsupported size unit %q", unit)
+	}
+	if value > math.MaxInt64/multiplier {
+		return 0, fmt.Errorf("size overflows int64")
+	}
+	return value * multiplier, nil
+}
+
+// SizeVar converts the given fileSize with a unit (b, kb, mb, gb, tb or kib, mib, gib, tib) to bytes.
+// kb, mb, gb and tb are aliases of kib, mib, gib and tib: '2kb' will be converted to 2048.
 // If no unit is provided, it will fallback to mb. e.g: '2' will be converted to 2097152.
 func (flagSet *FlagSet) SizeVar(field *Size, long string, defaultValue string, usage string) *FlagData {
 	return flagSet.SizeVarP(field, long, "", defaultValue, usage)
 }
 
-// SizeVarP converts the given fileSize with a unit (kb, mb, gb, or tb) to bytes.
-// For example, '2kb' will be converted to 2048.
+// SizeVarP converts the given fileSize with a unit (b, kb, mb, gb, tb or kib, mib, gib, tib) to bytes.
+// kb, mb, gb and tb are aliases of kib, mib, gib and tib: '2kb' will be converted to 2048.
 // If no unit is provided, it will fallback to mb. e.g: '2' will be converted to 2097152.
 func (flagSet *FlagSet) SizeVarP(field *Size, long, short string, defaultValue string, usage string) *FlagData {
 	if field == nil {
@@ -46,12 +90,15 @@ func (flagSet *FlagSet) SizeVarP(field *Size, long, short string, defaultValue s
 		long:         long,
 		defaultValue: defaultValue,
 	}
+	if !flagSet.checkDuplicateFlag(long, short) {
+		return flagData
+	}
 	if short != "" {
 		flagData.short = short
 		flagSet.CommandLine.Var(field, short, usage)
-		flagSet.flagKeys.Set(short, flagData)
+		flagSet.setFlagKey(short, flagData)
 	}
 	flagSet.CommandLine.Var(field, long, usage)
-	flagSet.flagKeys.Set(long, flagData)
+	flagSet.setFlagKey(long, flagData)
 	return flagData
 }
@@ -2,8 +2,10 @@ package goflags
 
 import (
 	"os"
+	"reflect"
 	"testing"
 
+	permissionutil "github.com/projectdiscovery/utils/permission"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -51,7 +53,56 @@ func TestSizeVar(t *testing.T) {
 		var fileSize Size
 		err := fileSize.Set("2kilobytes")
 		assert.NotNil(t, err)
-		assert.ErrorContains(t, err, "parse error")
+		assert.ErrorContains(t, err, `unsupported size unit "kilobytes"`)
 		tearDown(t.Name())
 	})
 }
+
+func TestSizeVarUnits(t *testing.T) {
+	tests := map[string]Size{
+		"512b":   512,
+		"10KB":   10 * 1024,
+		"10kib":  10 * 1024,
+		"10MB":   10 * 1024 * 1024,
+		"2MiB":   2 * 1024 * 1024,
+		"1GB":    1024 * 1024 * 1024,
+		"1GiB":   1024 * 1024 * 1024,
+		"8192TB": 8192 << 40,
+	}
+	for value, expected := range tests {
+		var fileSize Size
+		err := fileSize.Set(value)
+		assert.Nil(t, err, "could not parse %v", value)
+		assert.Equal(t, expected, fileSize, "could not get correct size for %v", value)
+	}
+
+	var fileSize Size
+	err := fileSize.Set("10XB")
+	assert.EqualError(t, err, `invalid size "10XB": unsupported size unit "xb"`)
+	err = fileSize.Set("8388608tb")
+	assert.EqualError(t, err, `invalid size "8388608tb": size overflows int64`)
+}
+
+func TestSizeVarConfig(t *testing.T) {
+	var withUnit, withoutUnit Size
+	flagSet := NewFlagSet()
+	flagSet.SizeVar(&withUnit, "max-size", "", "max size of the file")
+	flagSet.SizeVar(&withoutUnit, "min-size", "", "min size of the file")
+
+	configFileData := `
+max-size: 10MB
+min-size: 2`
+	err := os.WriteFile("test.yaml", []byte(configFileData), permissionutil.ConfigFilePermission)
+	assert.Nil(t, err, "could not write temporary config")
+	defer os.Remove("test.yaml")
+
+	err = flagSet.MergeConfigFile("test.yaml")
+	assert.Nil(t, err, "could not merge temporary config")
+	assert.Equal(t, Size(10*1024*1024), withUnit)
+	assert.Equal(t, Size(2*1024*1024), withoutUnit)
+
+	flag := flagSet.CommandLine.Lookup("max-size")
+	displayType, _ := usageTypeAndDescription(flag, reflect.TypeOf(flag.Value))
+	assert.Equal(t, "string", displayType)
+	tearDown(t.Name())
+}
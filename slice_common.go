@@ -1,7 +1,15 @@
 package goflags
 
 import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/pkg/errors"
 	fileutil "github.com/projectdiscovery/utils/file"
@@ -9,6 +17,15 @@ import (
 
 var quotes = []rune{'"', '\'', '`'}
 
+// stdinPath is the file path reading values from stdin for file options
+const stdinPath = "-"
+
+// stdin is the reader used for the stdin file path
+var stdin io.Reader = os.Stdin
+
+// defaultFetchTimeout is the timeout of url requests of file options
+const defaultFetchTimeout = 10 * time.Second
+
 func isQuote(char rune) (bool, rune) {
 	for _, quote := range quotes {
 		if quote == char {
@@ -45,70 +62,271 @@ func ToString(slice []string) string {
 	return defaultBuilder.String()
 }
 
+// MergeMode determines how command line values of a slice
+// flag are combined with the values from config files
+type MergeMode int
+
+const (
+	// MergeReplace ignores config values when the flag is given on the command line
+	MergeReplace MergeMode = iota
+	// MergeAppend appends command line values to config values
+	MergeAppend
+)
+
 type Options struct {
 	// IsFromFile determines if the values are from file
 	IsFromFile func(string) bool
 	// IsEmpty determines if the values are empty
 	IsEmpty func(string) bool
-	// Normalize the value (eg. removing trailing spaces)
+	// Normalize the value (eg. removing trailing spaces), applied to every
+	// element (cli, comma-separated or file line) before it is stored
 	Normalize func(string) string
 	// IsRaw determines if the value should be considered as a raw string
 	IsRaw func(string) bool
+	// Unique skips values already present in the slice preserving first-seen order
+	Unique bool
+	// IsClear determines if the value clears the slice, removing default and
+	// config values (ex: ClearOnEmpty for -flag "")
+	IsClear func(string) bool
+	// MergeMode determines how command line values are combined with config values
+	MergeMode MergeMode
+	// ExpandGlob expands file values containing glob patterns (ex: configs/*.txt)
+	// and reads the values of each matched file
+	ExpandGlob bool
+	// FetchURL reads the newline-separated values of http(s) urls given
+	// to file options (ex: https://example.com/targets.txt)
+	FetchURL bool
+	// FetchTimeout is the timeout of url requests, 10 seconds if zero
+	FetchTimeout time.Duration
+	// SplitFileLines splits each line read from files (or stdin and urls)
+	// on commas (ex: a value1,value2 line). Quotes are kept as is, commas
+	// escaped with a backslash are kept in the value (ex: a\,b).
+	SplitFileLines bool
+	// MaxItems limits the number of values of the slice from all sources
+	// (command line, config and files), checked on parse. Zero means no limit.
+	MaxItems int
+	// ElementValidator validates each value of the slice from all sources
+	// (repeated, comma-separated, file and config values), checked on parse
+	ElementValidator func(string) error
+	// ConfigCommaForm writes the default values of the flag in generated
+	// config files in comma form (ex: #flag: a,b) instead of a list, for
+	// options splitting values on commas
+	ConfigCommaForm bool
+	// TrimQuotes removes a single layer of matching surrounding quotes
+	// from each element (ex: "foo:bar" is stored as foo:bar)
+	TrimQuotes bool
+	// SkipEmpty drops the empty or whitespace-only elements, checked after
+	// normalization, for all the modes (ex: blank lines or trailing commas)
+	SkipEmpty bool
+	// SkipComments skips the lines starting with # read from files (or stdin
+	// and urls) and strips trailing comments at an unquoted # preceded by
+	// whitespace (ex: value # comment)
+	SkipComments bool
+	// Separator splits the values instead of a comma (ex: ';' or '|'),
+	// for command line, file and config values. Zero means a comma.
+	Separator rune
+}
+
+// separator returns the separator of comma-separated values
+func (options Options) separator() rune {
+	if options.Separator == 0 {
+		return ','
+	}
+	return options.Separator
+}
+
+// ClearOnEmpty is an Options.IsClear sentinel clearing
+// the slice when an empty value is given (ex: -flag "")
+func ClearOnEmpty(value string) bool {
+	return strings.TrimSpace(value) == ""
 }
 
 // ToStringSlice converts a value to string slice based on options
 func ToStringSlice(value string, options Options) ([]string, error) {
 	var result []string
 	if options.IsEmpty == nil && options.IsFromFile == nil && options.Normalize == nil {
+		if options.TrimQuotes {
+			value = trimSurroundingQuotes(value)
+		}
+		if options.SkipEmpty && isEmpty(value) {
+			return nil, nil
+		}
 		return []string{value}, nil
 	}
 
 	addPartToResult := func(part string) {
-		if !options.IsEmpty(part) {
+		if options.TrimQuotes {
+			part = trimSurroundingQuotes(part)
+		}
+		if options.IsEmpty == nil || !options.IsEmpty(part) {
 			if options.Normalize != nil {
 				part = options.Normalize(part)
 			}
+			if options.SkipEmpty && isEmpty(part) {
+				return
+			}
 			result = append(result, part)
 		}
 	}
-	if fileutil.FileExists(value) && options.IsFromFile != nil && options.IsFromFile(value) {
+	// lines from files, stdin or urls, split on commas with SplitFileLines
+	addLineToResult := func(line string) {
+		if options.SkipComments {
+			var hasComment bool
+			if line, hasComment = stripLineComment(line); hasComment && isEmpty(line) {
+				return
+			}
+		}
+		if !options.SplitFileLines {
+			addPartToResult(line)
+			return
+		}
+		splitSeparatorParts(line, options.separator(), addPartToResult)
+	}
+	if value == stdinPath && options.IsFromFile != nil && options.IsFromFile(value) {
+		scanner := bufio.NewScanner(stdin)
+		for scanner.Scan() {
+			addLineToResult(scanner.Text())
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+	} else if options.FetchURL && isHTTPURL(value) && options.IsFromFile != nil && options.IsFromFile(value) {
+		lines, err := fetchURLLines(value, options.FetchTimeout)
+		if err != nil {
+			return nil, err
+		}
+		for _, line := range lines {
+			addLineToResult(line)
+		}
+	} else if options.ExpandGlob && isGlobPattern(value) && options.IsFromFile != nil && options.IsFromFile(value) {
+		matches, err := filepath.Glob(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid glob pattern %q: %w", value, err)
+		}
+		if len(matches) == 0 {
+			return nil, fmt.Errorf("no files match glob pattern %q", value)
+		}
+		for _, match := range matches {
+			linesChan, err := fileutil.ReadFile(match)
+			if err != nil {
+				return nil, err
+			}
+			for line := range linesChan {
+				addLineToResult(line)
+			}
+		}
+	} else if fileutil.FileExists(value) && options.IsFromFile != nil && options.IsFromFile(value) {
 		linesChan, err := fileutil.ReadFile(value)
 		if err != nil {
 			return nil, err
 		}
 		for line := range linesChan {
-			addPartToResult(line)
+			addLineToResult(line)
 		}
 	} else if options.IsRaw != nil && options.IsRaw(value) {
 		addPartToResult(value)
 	} else {
-		index := 0
-		for index < len(value) {
-			char := rune(value[index])
-			if isQuote, quote := isQuote(char); isQuote {
-				quoteFound, part := searchPart(value[index+1:], quote)
+		if err := splitCommaParts(value, options.separator(), addPartToResult); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
 
-				if !quoteFound {
-					return nil, errors.New("Unclosed quote in path")
-				}
+// splitCommaParts calls addPart for each part of the value split on the
+// separator (a comma by default), keeping separators in quoted parts and
+// escaped separators (ex: \,)
+func splitCommaParts(value string, separator rune, addPart func(string)) error {
+	index := 0
+	for index < len(value) {
+		char := rune(value[index])
+		if isQuote, quote := isQuote(char); isQuote {
+			quoteFound, part := searchPart(value[index+1:], quote)
+
+			if !quoteFound {
+				return errors.New("Unclosed quote in path")
+			}
 
-				index += len(part) + 2
+			index += len(part) + 2
+
+			addPart(part)
+		} else {
+			part, consumed := searchSeparatorPart(value[index:], separator)
+			index += consumed
+
+			addPart(part)
+		}
+	}
+	return nil
+}
 
-				addPartToResult(part)
-			} else {
-				commaFound, part := searchPart(value[index:], ',')
+// splitSeparatorParts splits a value on the separator without parsing
+// quotes, so that file lines with apostrophes (ex: it's) are kept as is
+func splitSeparatorParts(value string, separator rune, addPart func(string)) {
+	for index := 0; index < len(value); {
+		part, consumed := searchSeparatorPart(value[index:], separator)
+		index += consumed
+		addPart(part)
+	}
+}
 
-				if commaFound {
-					index += len(part) + 1
-				} else {
-					index += len(part)
-				}
+// searchSeparatorPart returns the part of the value up to the first separator
+// not escaped with a backslash (ex: a\,b is the a,b part), and the number of
+// bytes consumed including the separator
+func searchSeparatorPart(value string, separator rune) (string, int) {
+	var part strings.Builder
+	escapedSeparator := "\\" + string(separator)
+	for index := 0; index < len(value); {
+		char, size := utf8.DecodeRuneInString(value[index:])
+		switch {
+		case strings.HasPrefix(value[index:], escapedSeparator):
+			part.WriteRune(separator)
+			index += len(escapedSeparator)
+		case char == separator:
+			return part.String(), index + size
+		default:
+			part.WriteString(value[index : index+size])
+			index += size
+		}
+	}
+	return part.String(), len(value)
+}
 
-				addPartToResult(part)
+// stripLineComment removes the comment starting at the first unquoted #
+// at the start of the line or preceded by whitespace
+func stripLineComment(line string) (string, bool) {
+	var quote rune
+	for i, char := range line {
+		if quote != 0 {
+			if char == quote {
+				quote = 0
 			}
+			continue
+		}
+		if isQuote, lineQuote := isQuote(char); isQuote {
+			quote = lineQuote
+			continue
+		}
+		if char == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t') {
+			return strings.TrimRight(line[:i], " \t"), true
 		}
 	}
-	return result, nil
+	return line, false
+}
+
+// trimSurroundingQuotes removes one layer of matching quotes around the value
+func trimSurroundingQuotes(value string) string {
+	if len(value) < 2 {
+		return value
+	}
+	if isQuote, quote := isQuote(rune(value[0])); isQuote && rune(value[len(value)-1]) == quote {
+		return value[1 : len(value)-1]
+	}
+	return value
+}
+
+func isGlobPattern(s string) bool {
+	return strings.ContainsAny(s, "*?[")
 }
 
 func isEmpty(s string) bool {
@@ -130,3 +348,34 @@ func normalize(s string) string {
 func normalizeLowercase(s string) string {
 	return strings.TrimSpace(strings.Trim(strings.TrimSpace(strings.ToLower(s)), string(quotes)))
 }
+
+// isHTTPURL returns true if the value is a http or https url
+func isHTTPURL(value string) bool {
+	return strings.HasPrefix(value, "http://") || strings.HasPrefix(value, "https://")
+}
+
+// fetchURLLines returns the lines of the body of a url
+func fetchURLLines(url string, timeout time.Duration) ([]string, error) {
+	if timeout == 0 {
+		timeout = defaultFetchTimeout
+	}
+	client := &http.Client{Timeout: timeout}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch %s: %w", url, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("could not fetch %s: unexpected status %s", url, resp.Status)
+	}
+
+	var lines []string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("could not read %s: %w", url, err)
+	}
+	return lines, nil
+}
@@ -19,10 +19,26 @@ type StringSlice []string
 
 // Set appends a value to the string slice.
 func (stringSlice *StringSlice) Set(value string) error {
+	return stringSlice.set(value, true)
+}
+
+// setInline appends a value to the string slice without reading it as a file
+func (stringSlice *StringSlice) setInline(value string) error {
+	return stringSlice.set(value, false)
+}
+
+func (stringSlice *StringSlice) set(value string, allowFile bool) error {
 	option, ok := optionMap[stringSlice]
 	if !ok {
 		option = StringSliceOptions
 	}
+	if !allowFile {
+		option.IsFromFile = nil
+	}
+	if option.IsClear != nil && option.IsClear(value) {
+		*stringSlice = StringSlice{}
+		return nil
+	}
 	values, err := ToStringSlice(value, option)
 	if err != nil {
 		return err
@@ -34,6 +50,14 @@ func (stringSlice *StringSlice) Set(value string) error {
 		}
 	}
 
+	if option.Unique {
+		for _, value := range values {
+			if !sliceutil.Contains(*stringSlice, value) {
+				*stringSlice = append(*stringSlice, value)
+			}
+		}
+		return nil
+	}
 	*stringSlice = append(*stringSlice, values...)
 	return nil
 }
@@ -17,17 +17,18 @@ var CommaSeparatedStringSliceOptions = Options{
 }
 
 // FileCommaSeparatedStringSliceOptions represents a list of comma separated files containing items
-// Tokenization: Comma
+// Tokenization: Comma (including file lines)
 // Normalization: None
 // Type: []string
 // test.txt content:
 // value1
-// value2
+// value2,value3
 //
-// Example: -flag test.txt => {"value1", "value2"}
+// Example: -flag test.txt => {"value1", "value2", "value3"}
 var FileCommaSeparatedStringSliceOptions = Options{
-	IsEmpty:    isEmpty,
-	IsFromFile: isFromFile,
+	IsEmpty:        isEmpty,
+	IsFromFile:     isFromFile,
+	SplitFileLines: true,
 }
 
 // NormalizedOriginalStringSliceOptions represents a list of items
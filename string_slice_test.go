@@ -1,10 +1,17 @@
 package goflags
 
 import (
+	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNormalizedStringSlice(t *testing.T) {
@@ -108,3 +115,189 @@ func TestFileNormalizedOriginalStringSliceOptions(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, []string{"Test User"}, result, "could not get correct path")
 }
+
+func TestStdinStringSliceOptions(t *testing.T) {
+	defer func(reader io.Reader) { stdin = reader }(stdin)
+
+	for name, options := range map[string]Options{
+		"FileStringSliceOptions":               FileStringSliceOptions,
+		"FileCommaSeparatedStringSliceOptions": FileCommaSeparatedStringSliceOptions,
+		"FileNormalizedStringSliceOptions":     FileNormalizedStringSliceOptions,
+	} {
+		t.Run(name, func(t *testing.T) {
+			stdin = strings.NewReader("target1\ntarget2\n\ntarget3")
+			result, err := ToStringSlice("-", options)
+			assert.Nil(t, err)
+			assert.Equal(t, []string{"target1", "target2", "target3"}, result, "could not read values from stdin")
+		})
+	}
+
+	t.Run("parse", func(t *testing.T) {
+		stdin = strings.NewReader("target1\ntarget2")
+		flagSet := NewFlagSet()
+		flagSet.DisableConfigLoading(true)
+		var targets StringSlice
+		flagSet.StringSliceVarP(&targets, "list", "l", nil, "List of targets", FileStringSliceOptions)
+
+		err := flagSet.ParseArgs([]string{"-list", "-"})
+		assert.Nil(t, err)
+		assert.Equal(t, StringSlice{"target1", "target2"}, targets)
+		tearDown(t.Name())
+	})
+
+	// stdin is not read for options without file support
+	stdin = strings.NewReader("target1")
+	result, err := ToStringSlice("-", CommaSeparatedStringSliceOptions)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"-"}, result)
+}
+
+func TestGlobStringSliceOptions(t *testing.T) {
+	dir := t.TempDir()
+	require.Nil(t, os.WriteFile(filepath.Join(dir, "a.txt"), []byte("value1\nvalue2"), 0644))
+	require.Nil(t, os.WriteFile(filepath.Join(dir, "b.txt"), []byte("value3"), 0644))
+	require.Nil(t, os.WriteFile(filepath.Join(dir, "c.log"), []byte("ignored"), 0644))
+
+	options := FileStringSliceOptions
+	options.ExpandGlob = true
+
+	result, err := ToStringSlice(filepath.Join(dir, "*.txt"), options)
+	require.Nil(t, err)
+	require.Equal(t, []string{"value1", "value2", "value3"}, result, "could not read values from matched files")
+
+	pattern := filepath.Join(dir, "*.yaml")
+	_, err = ToStringSlice(pattern, options)
+	require.EqualError(t, err, "no files match glob pattern \""+pattern+"\"")
+
+	// patterns are kept as values without the option
+	result, err = ToStringSlice(filepath.Join(dir, "*.txt"), FileStringSliceOptions)
+	require.Nil(t, err)
+	require.Equal(t, []string{filepath.Join(dir, "*.txt")}, result)
+}
+
+func TestURLStringSliceOptions(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/targets.txt" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte("value1\nvalue2\n\nvalue3"))
+	}))
+	defer server.Close()
+
+	options := FileStringSliceOptions
+	options.FetchURL = true
+	options.FetchTimeout = 5 * time.Second
+
+	result, err := ToStringSlice(server.URL+"/targets.txt", options)
+	require.Nil(t, err)
+	require.Equal(t, []string{"value1", "value2", "value3"}, result, "could not read values from url")
+
+	_, err = ToStringSlice(server.URL+"/missing.txt", options)
+	require.EqualError(t, err, "could not fetch "+server.URL+"/missing.txt: unexpected status 404 Not Found")
+
+	// urls are kept as values without the option
+	result, err = ToStringSlice(server.URL+"/targets.txt", FileStringSliceOptions)
+	require.Nil(t, err)
+	require.Equal(t, []string{server.URL + "/targets.txt"}, result)
+}
+
+func TestFileCommaSeparatedLines(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "targets.txt")
+	require.Nil(t, os.WriteFile(filename, []byte("value1\nvalue2,value3\nvalue4\\,with comma,value5"), 0644))
+
+	result, err := ToStringSlice(filename, FileCommaSeparatedStringSliceOptions)
+	require.Nil(t, err)
+	require.Equal(t, []string{"value1", "value2", "value3", "value4,with comma", "value5"}, result, "could not split file lines on commas")
+
+	// whole lines are kept without the option
+	result, err = ToStringSlice(filename, FileStringSliceOptions)
+	require.Nil(t, err)
+	require.Equal(t, []string{"value1", "value2,value3", "value4\\,with comma,value5"}, result)
+
+	// quotes are not parsed in file lines, so apostrophes are kept
+	require.Nil(t, os.WriteFile(filename, []byte("it's\n'quoted,don't\nvalue6,'value7"), 0644))
+	result, err = ToStringSlice(filename, FileCommaSeparatedStringSliceOptions)
+	require.Nil(t, err)
+	require.Equal(t, []string{"it's", "'quoted", "don't", "value6", "'value7"}, result)
+}
+
+func TestEscapedCommaStringSlice(t *testing.T) {
+	for value, expected := range map[string][]string{
+		`a\,b,c`:       {"a,b", "c"},
+		`"a,b",c`:      {"a,b", "c"},
+		`a\,b\,c`:      {"a,b,c"},
+		`"x,y",a\,b,c`: {"x,y", "a,b", "c"},
+		`c:\dir\file`:  {`c:\dir\file`},
+	} {
+		result, err := ToStringSlice(value, CommaSeparatedStringSliceOptions)
+		require.Nil(t, err)
+		require.Equal(t, expected, result, "could not split %v", value)
+	}
+}
+
+func TestSeparatorStringSlice(t *testing.T) {
+	options := CommaSeparatedStringSliceOptions
+	options.Separator = ';'
+
+	flagSet := NewFlagSet()
+	flagSet.DisableConfigLoading(true)
+	var values StringSlice
+	flagSet.StringSliceVar(&values, "values", nil, "semicolon separated values", options)
+
+	err := flagSet.ParseArgs([]string{"-values", "a;b;c"})
+	require.Nil(t, err)
+	require.Equal(t, StringSlice{"a", "b", "c"}, values)
+	tearDown(t.Name())
+
+	result, err := ToStringSlice(`a,b;"c;d";e\;f`, options)
+	require.Nil(t, err)
+	require.Equal(t, []string{"a,b", "c;d", "e;f"}, result, "commas should be kept with another separator")
+
+	filename := filepath.Join(t.TempDir(), "values.txt")
+	require.Nil(t, os.WriteFile(filename, []byte("a;b\nc"), 0644))
+	fileOptions := FileCommaSeparatedStringSliceOptions
+	fileOptions.Separator = ';'
+	result, err = ToStringSlice(filename, fileOptions)
+	require.Nil(t, err)
+	require.Equal(t, []string{"a", "b", "c"}, result, "could not split file lines on the separator")
+}
+
+func TestSkipEmptyStringSlice(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "targets.txt")
+	require.Nil(t, os.WriteFile(filename, []byte("value1\n\n   \n''\nvalue2\n\"\"\n"), 0644))
+
+	// quoted empty lines are normalized to empty values without the option
+	result, err := ToStringSlice(filename, FileNormalizedOriginalStringSliceOptions)
+	require.Nil(t, err)
+	require.Equal(t, []string{"value1", "", "value2", ""}, result)
+
+	options := FileNormalizedOriginalStringSliceOptions
+	options.SkipEmpty = true
+	result, err = ToStringSlice(filename, options)
+	require.Nil(t, err)
+	require.Equal(t, []string{"value1", "value2"}, result, "empty values should be skipped")
+
+	options = StringSliceOptions
+	options.SkipEmpty = true
+	result, err = ToStringSlice("  ", options)
+	require.Nil(t, err)
+	require.Empty(t, result)
+}
+
+func TestSkipCommentsStringSlice(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "targets.txt")
+	content := "# targets for the scan\nexample.com\n   # indented comment\napi.example.com # production api\n\"value # quoted\"\nhttps://example.com/#fragment\n"
+	require.Nil(t, os.WriteFile(filename, []byte(content), 0644))
+
+	options := FileStringSliceOptions
+	options.SkipComments = true
+	result, err := ToStringSlice(filename, options)
+	require.Nil(t, err)
+	require.Equal(t, []string{"example.com", "api.example.com", "\"value # quoted\"", "https://example.com/#fragment"}, result)
+
+	// comments are kept as values without the option
+	result, err = ToStringSlice(filename, FileStringSliceOptions)
+	require.Nil(t, err)
+	require.Contains(t, result, "# targets for the scan")
+}
@@ -0,0 +1,85 @@
+package goflags
+
+import "strings"
+
+// maxSuggestDistance is the maximum edit distance of a suggested flag name
+const maxSuggestDistance = 2
+
+// checkUnknownFlags returns an error for the first unknown flag of the
+// arguments, walking them like the flag package does until the first
+// non-flag argument or the "--" terminator.
+func (flagSet *FlagSet) checkUnknownFlags(args []string) error {
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if len(arg) < 2 || arg[0] != '-' || arg == "--" {
+			return nil
+		}
+		name := strings.TrimPrefix(arg[1:], "-")
+		name, _, hasValue := strings.Cut(name, "=")
+		if name == "" || name[0] == '-' || name[0] == '=' {
+			// bad flag syntax is reported by the flag package
+			return nil
+		}
+		currentFlag := flagSet.CommandLine.Lookup(name)
+		if currentFlag == nil {
+			if isHelpArg("-" + name) {
+				continue
+			}
+			return flagSet.unknownFlagError(name)
+		}
+		if boolFlag, ok := currentFlag.Value.(interface{ IsBoolFlag() bool }); ok && boolFlag.IsBoolFlag() {
+			continue
+		}
+		if !hasValue {
+			// the next argument is the value of the flag
+			i++
+		}
+	}
+	return nil
+}
+
+// unknownFlagError returns the error for an unknown flag name with the
+// closest visible registered flag name as suggestion, if any.
+func (flagSet *FlagSet) unknownFlagError(name string) error {
+	var suggestion string
+	bestDistance := maxSuggestDistance + 1
+	flagSet.flagKeys.forEach(func(key string, data *FlagData) {
+		if flagSet.isHidden(data) || flagSet.CommandLine.Lookup(key) == nil {
+			return
+		}
+		if distance := editDistance(name, key); distance < bestDistance {
+			suggestion, bestDistance = key, distance
+		}
+	})
+	if suggestion == "" {
+		return newParseError(CategoryUnknown, name, "", "flag provided but not defined: -%v", name)
+	}
+	return newParseError(CategoryUnknown, name, "", "flag provided but not defined: -%v (did you mean -%v?)", name, suggestion)
+}
+
+// editDistance returns the levenshtein distance between two strings
+func editDistance(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = previous[j-1] + cost
+			if previous[j]+1 < current[j] {
+				current[j] = previous[j] + 1
+			}
+			if current[j-1]+1 < current[j] {
+				current[j] = current[j-1] + 1
+			}
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
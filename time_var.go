@@ -0,0 +1,76 @@
+package goflags
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultTimeLayouts are the layouts used by TimeVar when none are given
+var DefaultTimeLayouts = []string{time.RFC3339, time.DateOnly}
+
+type timeValue struct {
+	value   *time.Time
+	layouts []string
+}
+
+func (t *timeValue) Set(s string) error {
+	for _, layout := range t.layouts {
+		if parsed, err := time.Parse(layout, s); err == nil {
+			*t.value = parsed
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid time %q: expected layouts %s", s, strings.Join(t.layouts, ", "))
+}
+
+func (t *timeValue) Get() any { return *t.value }
+
+func (t *timeValue) String() string {
+	if t.value == nil || t.value.IsZero() {
+		return ""
+	}
+	return t.value.Format(t.layouts[0])
+}
+
+func (t *timeValue) displayType() string { return "string" }
+
+// TimeVar adds a time flag with a longname
+func (flagSet *FlagSet) TimeVar(field *time.Time, long string, defaultValue string, usage string, layouts ...string) *FlagData {
+	return flagSet.TimeVarP(field, long, "", defaultValue, usage, layouts...)
+}
+
+// TimeVarP adds a time flag with a shortname and longname.
+// The value is parsed with the first matching layout, RFC3339 and
+// date-only (ex: 2024-01-01) if no layouts are given.
+func (flagSet *FlagSet) TimeVarP(field *time.Time, long, short string, defaultValue string, usage string, layouts ...string) *FlagData {
+	if field == nil {
+		panic(fmt.Errorf("field cannot be nil for flag -%v", long))
+	}
+	if len(layouts) == 0 {
+		layouts = DefaultTimeLayouts
+	}
+	value := &timeValue{value: field, layouts: layouts}
+	*field = time.Time{}
+	if defaultValue != "" {
+		if err := value.Set(defaultValue); err != nil {
+			panic(fmt.Errorf("failed to set default value for flag -%v: %v", long, err))
+		}
+	}
+	flagData := &FlagData{
+		usage:        usage,
+		long:         long,
+		defaultValue: defaultValue,
+	}
+	if !flagSet.checkDuplicateFlag(long, short) {
+		return flagData
+	}
+	if short != "" {
+		flagData.short = short
+		flagSet.CommandLine.Var(value, short, usage)
+		flagSet.setFlagKey(short, flagData)
+	}
+	flagSet.CommandLine.Var(value, long, usage)
+	flagSet.setFlagKey(long, flagData)
+	return flagData
+}
@@ -0,0 +1,64 @@
+package goflags
+
+import (
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	permissionutil "github.com/projectdiscovery/utils/permission"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeVar(t *testing.T) {
+	t.Run("layouts", func(t *testing.T) {
+		tests := map[string]time.Time{
+			"2024-01-01":           time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+			"2024-01-01T12:00:00Z": time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+		}
+		for value, expected := range tests {
+			var since time.Time
+			flagSet := NewFlagSet()
+			flagSet.TimeVarP(&since, "since", "s", "", "start time")
+			err := flagSet.ParseArgs([]string{"-since", value})
+			assert.Nil(t, err)
+			assert.True(t, expected.Equal(since), "could not get correct time for %v", value)
+			tearDown(t.Name())
+		}
+	})
+
+	t.Run("custom-layout", func(t *testing.T) {
+		var since time.Time
+		err := (&timeValue{value: &since, layouts: []string{"02/01/2006"}}).Set("31/12/2023")
+		assert.Nil(t, err)
+		assert.True(t, time.Date(2023, 12, 31, 0, 0, 0, 0, time.UTC).Equal(since))
+	})
+
+	t.Run("invalid-time", func(t *testing.T) {
+		var since time.Time
+		err := (&timeValue{value: &since, layouts: DefaultTimeLayouts}).Set("yesterday")
+		assert.EqualError(t, err, `invalid time "yesterday": expected layouts 2006-01-02T15:04:05Z07:00, 2006-01-02`)
+	})
+
+	t.Run("config-file", func(t *testing.T) {
+		var since, until time.Time
+		flagSet := NewFlagSet()
+		flagSet.TimeVar(&since, "since", "", "start time")
+		flagSet.TimeVar(&until, "until", "", "end time")
+
+		err := os.WriteFile("test.yaml", []byte("since: 2024-01-01\nuntil: \"2024-02-01T10:00:00Z\""), permissionutil.ConfigFilePermission)
+		require.Nil(t, err, "could not write temporary config")
+		defer os.Remove("test.yaml")
+
+		err = flagSet.MergeConfigFile("test.yaml")
+		require.Nil(t, err, "could not merge temporary config")
+		assert.True(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).Equal(since))
+		assert.True(t, time.Date(2024, 2, 1, 10, 0, 0, 0, time.UTC).Equal(until))
+
+		flag := flagSet.CommandLine.Lookup("since")
+		displayType, _ := usageTypeAndDescription(flag, reflect.TypeOf(flag.Value))
+		assert.Equal(t, "string", displayType)
+		tearDown(t.Name())
+	})
+}
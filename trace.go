@@ -0,0 +1,47 @@
+package goflags
+
+import (
+	"encoding/json"
+)
+
+// trace event types
+const (
+	traceFlagSet        = "flag_set"
+	traceSourceResolved = "source_resolved"
+	traceValidation     = "validation"
+)
+
+// traceEvent is a parse event written as a JSON line to the trace writer
+type traceEvent struct {
+	Event  string `json:"event"`
+	Flag   string `json:"flag,omitempty"`
+	Value  string `json:"value,omitempty"`
+	Source string `json:"source,omitempty"`
+	Result string `json:"result,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// trace writes the event to the trace writer if tracing is enabled
+func (flagSet *FlagSet) trace(event traceEvent) {
+	if flagSet.traceWriter == nil {
+		return
+	}
+	if data, ok := flagSet.flagKeys.values[event.Flag]; ok && data.secret && event.Value != "" {
+		event.Value = secretMask
+	}
+	data, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	_, _ = flagSet.traceWriter.Write(append(data, '\n'))
+}
+
+// traceValidation writes the result of the flag validation
+func (flagSet *FlagSet) traceValidation(err error) {
+	event := traceEvent{Event: traceValidation, Result: "passed"}
+	if err != nil {
+		event.Result = "failed"
+		event.Error = err.Error()
+	}
+	flagSet.trace(event)
+}
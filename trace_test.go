@@ -0,0 +1,40 @@
+package goflags
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTraceWriter(t *testing.T) {
+	flagSet := NewFlagSet()
+	var stringData string
+	var intData int
+	flagSet.StringVarP(&stringData, "string-value", "sv", "", "String value example")
+	flagSet.IntVar(&intData, "int-value", 10, "Int value example")
+
+	output := &bytes.Buffer{}
+	flagSet.SetTraceWriter(output)
+	os.Args = []string{
+		os.Args[0],
+		"-sv", "test",
+	}
+	err := flagSet.Parse()
+	require.Nil(t, err)
+
+	var events []traceEvent
+	scanner := bufio.NewScanner(output)
+	for scanner.Scan() {
+		var event traceEvent
+		require.Nil(t, json.Unmarshal(scanner.Bytes(), &event), "could not unmarshal trace event")
+		events = append(events, event)
+	}
+	require.Contains(t, events, traceEvent{Event: traceFlagSet, Flag: "sv", Value: "test", Source: "cli"})
+	require.Contains(t, events, traceEvent{Event: traceSourceResolved, Flag: "int-value", Value: "10", Source: "default"})
+	require.Equal(t, traceEvent{Event: traceValidation, Result: "passed"}, events[len(events)-1])
+	tearDown(t.Name())
+}
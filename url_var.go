@@ -0,0 +1,67 @@
+package goflags
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+)
+
+type urlValue string
+
+func newURLValue(val string, p *string) *urlValue {
+	*p = val
+	return (*urlValue)(p)
+}
+
+func (u *urlValue) Set(s string) error {
+	parsed, err := url.ParseRequestURI(s)
+	if err != nil {
+		return fmt.Errorf("invalid url %q: %v", s, errors.Unwrap(err))
+	}
+	if parsed.Scheme == "" || parsed.Host == "" {
+		return fmt.Errorf("invalid url %q: missing scheme or host", s)
+	}
+	*u = urlValue(s)
+	return nil
+}
+
+func (u *urlValue) String() string { return string(*u) }
+
+func (u *urlValue) Get() any { return string(*u) }
+
+func (u *urlValue) displayType() string { return "string" }
+
+// URLVar adds a url flag with a longname
+func (flagSet *FlagSet) URLVar(field *string, long string, defaultValue string, usage string) *FlagData {
+	return flagSet.URLVarP(field, long, "", defaultValue, usage)
+}
+
+// URLVarP adds a url flag with a shortname and longname.
+// The value is validated to be an absolute url with a scheme and host (ex: https://example.com)
+// and stored as the validated string.
+func (flagSet *FlagSet) URLVarP(field *string, long, short string, defaultValue string, usage string) *FlagData {
+	if field == nil {
+		panic(fmt.Errorf("field cannot be nil for flag -%v", long))
+	}
+	if defaultValue != "" {
+		if err := (*urlValue)(field).Set(defaultValue); err != nil {
+			panic(fmt.Errorf("failed to set default value for flag -%v: %v", long, err))
+		}
+	}
+	flagData := &FlagData{
+		usage:        usage,
+		long:         long,
+		defaultValue: defaultValue,
+	}
+	if !flagSet.checkDuplicateFlag(long, short) {
+		return flagData
+	}
+	if short != "" {
+		flagData.short = short
+		flagSet.CommandLine.Var(newURLValue(defaultValue, field), short, usage)
+		flagSet.setFlagKey(short, flagData)
+	}
+	flagSet.CommandLine.Var(newURLValue(defaultValue, field), long, usage)
+	flagSet.setFlagKey(long, flagData)
+	return flagData
+}
@@ -0,0 +1,72 @@
+package goflags
+
+import (
+	"flag"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	permissionutil "github.com/projectdiscovery/utils/permission"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestURLVar(t *testing.T) {
+	t.Run("valid-url", func(t *testing.T) {
+		var target string
+		flagSet := NewFlagSet()
+		flagSet.CreateGroup("Input", "Input",
+			flagSet.URLVarP(&target, "url", "u", "", "target url"),
+		)
+		os.Args = []string{
+			os.Args[0],
+			"-url", "https://example.com/path?query=1",
+		}
+		err := flagSet.Parse()
+		assert.Nil(t, err)
+		assert.Equal(t, "https://example.com/path?query=1", target)
+		tearDown(t.Name())
+	})
+
+	t.Run("config-file", func(t *testing.T) {
+		var target string
+		flagSet := NewFlagSet()
+		flagSet.URLVar(&target, "url", "", "target url")
+
+		err := os.WriteFile("test.yaml", []byte("url: https://example.com"), permissionutil.ConfigFilePermission)
+		require.Nil(t, err, "could not write temporary config")
+		defer os.Remove("test.yaml")
+
+		err = flagSet.MergeConfigFile("test.yaml")
+		require.Nil(t, err, "could not merge temporary config")
+		assert.Equal(t, "https://example.com", target)
+		tearDown(t.Name())
+	})
+
+	t.Run("without-scheme", func(t *testing.T) {
+		var target string
+		err := newURLValue("", &target).Set("example.com/path")
+		assert.NotNil(t, err)
+		assert.ErrorContains(t, err, "invalid url \"example.com/path\"")
+		assert.Empty(t, target)
+		tearDown(t.Name())
+	})
+
+	t.Run("invalid-url", func(t *testing.T) {
+		var target string
+		err := newURLValue("", &target).Set("::not a url::")
+		assert.NotNil(t, err)
+		assert.ErrorContains(t, err, "invalid url")
+		assert.Empty(t, target)
+		tearDown(t.Name())
+	})
+
+	t.Run("usage-type", func(t *testing.T) {
+		var target string
+		currentFlag := &flag.Flag{Value: newURLValue("", &target)}
+		result := createUsageTypeAndDescription(currentFlag, reflect.TypeOf(currentFlag.Value))
+		assert.Equal(t, "string", strings.TrimSpace(result))
+		tearDown(t.Name())
+	})
+}
@@ -0,0 +1,37 @@
+package goflags
+
+import (
+	"fmt"
+	"os"
+)
+
+// defaultVersionFormat is the default output format of VersionVar
+const defaultVersionFormat = "%s\n"
+
+// VersionVar adds a -version callback flag printing the version of the
+// project and exiting, unless exiting is disabled with SetExitOnHelp(false).
+// The output can be customized with SetVersionFormat.
+func (flagSet *FlagSet) VersionVar(version string) *FlagData {
+	return flagSet.VersionVarP(version, "")
+}
+
+// VersionVarP adds a -version callback flag with a shortname (ex: "v")
+// printing the version of the project, like VersionVar.
+func (flagSet *FlagSet) VersionVarP(version, short string) *FlagData {
+	return flagSet.CallbackVarP(func() {
+		format := flagSet.versionFormat
+		if format == "" {
+			format = defaultVersionFormat
+		}
+		fmt.Fprintf(flagSet.outputWriter(), format, version)
+		if !flagSet.disableExitOnHelp {
+			os.Exit(0)
+		}
+	}, "version", short, "show version of the project")
+}
+
+// SetVersionFormat sets the format of the version printed by VersionVar
+// with a %s verb for the version (ex: "Current Version: %s\n").
+func (flagSet *FlagSet) SetVersionFormat(format string) {
+	flagSet.versionFormat = format
+}
@@ -0,0 +1,53 @@
+package goflags
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersionVar(t *testing.T) {
+	flagSet := NewFlagSet()
+	flagSet.SetExitOnHelp(false)
+	flagSet.SetVersionFormat("Current Version: %s\n")
+	flagSet.VersionVar("v1.4.0")
+
+	reader, writer, err := os.Pipe()
+	require.Nil(t, err, "could not create pipe")
+	stdout := os.Stdout
+	os.Stdout = writer
+	err = flagSet.ParseArgs([]string{"-version"})
+	os.Stdout = stdout
+	writer.Close()
+	output, _ := io.ReadAll(reader)
+
+	require.Nil(t, err)
+	require.Equal(t, "Current Version: v1.4.0\n", string(output))
+	tearDown(t.Name())
+}
+
+func TestVersionVarP(t *testing.T) {
+	flagSet := NewFlagSet()
+	flagSet.DisableConfigLoading(true)
+	flagSet.SetExitOnHelp(false)
+	output := &bytes.Buffer{}
+	flagSet.SetOutput(output)
+	var verbose bool
+	flagSet.BoolVarP(&verbose, "verbose", "v", false, "verbose output")
+	require.NotPanics(t, func() {
+		flagSet.VersionVar("v1.4.0")
+	}, "version flag should not take the -v short name")
+	require.Equal(t, "verbose output", flagSet.Lookup("v").Usage)
+
+	flagSet = NewFlagSet()
+	flagSet.DisableConfigLoading(true)
+	flagSet.SetExitOnHelp(false)
+	flagSet.SetOutput(output)
+	flagSet.VersionVarP("v1.4.0", "V")
+	require.Nil(t, flagSet.ParseArgs([]string{"-V"}))
+	require.Equal(t, "v1.4.0\n", output.String())
+	tearDown(t.Name())
+}